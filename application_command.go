@@ -472,42 +472,56 @@ func UnmarshalApplicationCommandOption(buf []byte) (ApplicationCommandOption, er
 		return nil, err
 	}
 
-	switch meta.Type {
+	o := applicationCommandOptionOfType(meta.Type)
+	if o == nil {
+		return nil, errors.New("unknown application command option type")
+	}
+	return o, json.Unmarshal(buf, o)
+}
+
+// unmarshalApplicationCommandOptionETF is the ETF counterpart of UnmarshalApplicationCommandOption.
+func unmarshalApplicationCommandOptionETF(d *etfDecoder) (ApplicationCommandOption, error) {
+	var meta struct {
+		Type ApplicationCommandOptionType `json:"type"`
+	}
+	if err := d.peek(&meta); err != nil {
+		return nil, err
+	}
+
+	o := applicationCommandOptionOfType(meta.Type)
+	if o == nil {
+		return nil, errors.New("unknown application command option type")
+	}
+	return o, d.unmarshal(o)
+}
+
+// applicationCommandOptionOfType returns a new option of the given type, or nil if the type is unknown.
+func applicationCommandOptionOfType(optionType ApplicationCommandOptionType) ApplicationCommandOption {
+	switch optionType {
 	case ApplicationCommandOptionTypeSubCommand:
-		var o ApplicationCommandOptionSubCommand
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionSubCommand{}
 	case ApplicationCommandOptionTypeSubCommandGroup:
-		var o ApplicationCommandOptionSubCommand
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionSubCommand{}
 	case ApplicationCommandOptionTypeString:
-		var o ApplicationCommandOptionString
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionString{}
 	case ApplicationCommandOptionTypeInteger:
-		var o ApplicationCommandOptionInteger
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionInteger{}
 	case ApplicationCommandOptionTypeBool:
-		var o ApplicationCommandOptionBool
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionBool{}
 	case ApplicationCommandOptionTypeUser:
-		var o ApplicationCommandOptionUser
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionUser{}
 	case ApplicationCommandOptionTypeChannel:
-		var o ApplicationCommandOptionChannel
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionChannel{}
 	case ApplicationCommandOptionTypeRole:
-		var o ApplicationCommandOptionRole
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionRole{}
 	case ApplicationCommandOptionTypeMentionable:
-		var o ApplicationCommandOptionMentionable
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionMentionable{}
 	case ApplicationCommandOptionTypeFloat:
-		var o ApplicationCommandOptionFloat
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionFloat{}
 	case ApplicationCommandOptionTypeAttachment:
-		var o ApplicationCommandOptionAttachment
-		return &o, json.Unmarshal(buf, &o)
+		return &ApplicationCommandOptionAttachment{}
 	default:
-		return nil, errors.New("unknown application command option type")
+		return nil
 	}
 }
 
@@ -840,21 +854,42 @@ func UnmarshalApplicationCommand(buf []byte) (ApplicationCommand, error) {
 		return nil, err
 	}
 
-	switch meta.Type {
+	c := applicationCommandOfType(meta.Type)
+	if c == nil {
+		return nil, errors.New("unknown application command type")
+	}
+	return c, json.Unmarshal(buf, c)
+}
+
+// unmarshalApplicationCommandETF is the ETF counterpart of UnmarshalApplicationCommand.
+func unmarshalApplicationCommandETF(d *etfDecoder) (ApplicationCommand, error) {
+	var meta struct {
+		Type ApplicationCommandType `json:"type"`
+	}
+	if err := d.peek(&meta); err != nil {
+		return nil, err
+	}
+
+	c := applicationCommandOfType(meta.Type)
+	if c == nil {
+		return nil, errors.New("unknown application command type")
+	}
+	return c, d.unmarshal(c)
+}
+
+// applicationCommandOfType returns a new command of the given type, or nil if the type is unknown.
+func applicationCommandOfType(commandType ApplicationCommandType) ApplicationCommand {
+	switch commandType {
 	case ApplicationCommandTypeChatInput:
-		var c ChatInputCommand
-		return &c, json.Unmarshal(buf, &c)
+		return &ChatInputCommand{}
 	case ApplicationCommandTypeUser:
-		var c ApplicationUserCommand
-		return &c, json.Unmarshal(buf, &c)
+		return &ApplicationUserCommand{}
 	case ApplicationCommandTypeMessage:
-		var c ApplicationMessageCommand
-		return &c, json.Unmarshal(buf, &c)
+		return &ApplicationMessageCommand{}
 	case ApplicationCommandTypePrimaryEntryPoint:
-		var c ApplicationEntryPointCommand
-		return &c, json.Unmarshal(buf, &c)
+		return &ApplicationEntryPointCommand{}
 	default:
-		return nil, errors.New("unknown application command type")
+		return nil
 	}
 }
 
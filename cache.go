@@ -24,11 +24,12 @@ const (
 	CacheFlagChannels
 	CacheFlagRoles
 	CacheFlagVoiceStates
+	CacheFlagSoundboardSounds
 
 	CacheFlagsNone CacheFlags = 0
 
 	CacheFlagsAll = CacheFlagUsers | CacheFlagGuilds | CacheFlagMembers | CacheFlagThreadMembers |
-		CacheFlagMessages | CacheFlagChannels | CacheFlagRoles | CacheFlagVoiceStates
+		CacheFlagMessages | CacheFlagChannels | CacheFlagRoles | CacheFlagVoiceStates | CacheFlagSoundboardSounds
 )
 
 func (f CacheFlags) Has(bits ...CacheFlags) bool {
@@ -54,6 +55,8 @@ type CacheManager interface {
 	GetGuildMembers(guildID Snowflake) (map[Snowflake]Member, bool)
 	GetGuildVoiceStates(guildID Snowflake) (map[Snowflake]VoiceState, bool)
 	GetGuildRoles(guildID Snowflake) (map[Snowflake]Role, bool)
+	GetSoundboardSound(guildID, soundID Snowflake) (SoundBoardSound, bool)
+	GetGuildSoundboardSounds(guildID Snowflake) (map[Snowflake]SoundBoardSound, bool)
 
 	HasUser(userID Snowflake) bool
 	HasGuild(guildID Snowflake) bool
@@ -65,6 +68,7 @@ type CacheManager interface {
 	HasGuildMembers(guildID Snowflake) bool
 	HasGuildVoiceStates(guildID Snowflake) bool
 	HasGuildRoles(guildID Snowflake) bool
+	HasSoundboardSound(guildID, soundID Snowflake) bool
 
 	CountUsers() int
 	CountGuilds() int
@@ -76,6 +80,8 @@ type CacheManager interface {
 	CountGuildChannels(guildID Snowflake) int
 	CountGuildMembers(guildID Snowflake) int
 	CountGuildRoles(guildID Snowflake) int
+	CountSoundboardSounds() int
+	CountGuildSoundboardSounds(guildID Snowflake) int
 
 	PutUser(user User)
 	PutGuild(guild Guild)
@@ -84,6 +90,7 @@ type CacheManager interface {
 	PutMessage(message Message)
 	PutVoiceState(voiceState VoiceState)
 	PutRole(role Role)
+	PutSoundboardSound(sound SoundBoardSound)
 
 	DelUser(userID Snowflake) bool
 	DelGuild(guildID Snowflake) bool
@@ -94,6 +101,8 @@ type CacheManager interface {
 	DelGuildChannels(guildID Snowflake) bool
 	DelGuildMembers(guildID Snowflake) bool
 	DelRole(guildID, roleID Snowflake) bool
+	DelSoundboardSound(guildID, soundID Snowflake) bool
+	DelGuildSoundboardSounds(guildID Snowflake) bool
 }
 
 // DefaultCache is a high-performance cache implementation using 256-way sharding.
@@ -110,12 +119,14 @@ type DefaultCache struct {
 	messagesCache *ShardMap[Snowflake, Message]
 	voiceStates   *ShardMap[SnowflakePairKey, VoiceState]
 	rolesCache    *ShardMap[Snowflake, Role]
+	soundsCache   *ShardMap[SnowflakePairKey, SoundBoardSound]
 
 	// Sharded indexes for guild-to-entity relationships
 	guildToMemberIDs         *shardedIndex // guildID -> set[userID]
 	guildToChannelIDs        *shardedIndex // guildID -> set[channelID]
 	guildToVoiceStateUserIDs *shardedIndex // guildID -> set[userID]
 	guildToRoleIDs           *shardedIndex // guildID -> set[roleID]
+	guildToSoundIDs          *shardedIndex // guildID -> set[soundID]
 }
 
 func NewDefaultCache(flags CacheFlags) CacheManager {
@@ -128,10 +139,12 @@ func NewDefaultCache(flags CacheFlags) CacheManager {
 		messagesCache:            NewSnowflakeShardMap[Message](),
 		voiceStates:              NewSnowflakePairShardMap[VoiceState](),
 		rolesCache:               NewSnowflakeShardMap[Role](),
+		soundsCache:              NewSnowflakePairShardMap[SoundBoardSound](),
 		guildToMemberIDs:         newShardedIndex(),
 		guildToChannelIDs:        newShardedIndex(),
 		guildToVoiceStateUserIDs: newShardedIndex(),
 		guildToRoleIDs:           newShardedIndex(),
+		guildToSoundIDs:          newShardedIndex(),
 	}
 }
 
@@ -230,6 +243,25 @@ func (c *DefaultCache) GetGuildRoles(guildID Snowflake) (map[Snowflake]Role, boo
 	return res, true
 }
 
+func (c *DefaultCache) GetSoundboardSound(guildID, soundID Snowflake) (SoundBoardSound, bool) {
+	return c.soundsCache.Get(SnowflakePairKey{A: guildID, B: soundID})
+}
+
+func (c *DefaultCache) GetGuildSoundboardSounds(guildID Snowflake) (map[Snowflake]SoundBoardSound, bool) {
+	set, ok := c.guildToSoundIDs.Get(guildID)
+	if !ok {
+		return nil, false
+	}
+	res := make(map[Snowflake]SoundBoardSound, len(set))
+	for soundID := range set {
+		key := SnowflakePairKey{A: guildID, B: soundID}
+		if sound, exists := c.soundsCache.Get(key); exists {
+			res[soundID] = sound
+		}
+	}
+	return res, true
+}
+
 func (c *DefaultCache) HasUser(userID Snowflake) bool {
 	if !c.flags.Has(CacheFlagUsers) {
 		return false
@@ -300,6 +332,13 @@ func (c *DefaultCache) HasGuildRoles(guildID Snowflake) bool {
 	return c.guildToRoleIDs.Has(guildID)
 }
 
+func (c *DefaultCache) HasSoundboardSound(guildID, soundID Snowflake) bool {
+	if !c.flags.Has(CacheFlagSoundboardSounds) {
+		return false
+	}
+	return c.soundsCache.Has(SnowflakePairKey{A: guildID, B: soundID})
+}
+
 func (c *DefaultCache) CountUsers() int {
 	return c.usersCache.Len()
 }
@@ -340,6 +379,14 @@ func (c *DefaultCache) CountGuildRoles(guildID Snowflake) int {
 	return c.guildToRoleIDs.Count(guildID)
 }
 
+func (c *DefaultCache) CountSoundboardSounds() int {
+	return c.soundsCache.Len()
+}
+
+func (c *DefaultCache) CountGuildSoundboardSounds(guildID Snowflake) int {
+	return c.guildToSoundIDs.Count(guildID)
+}
+
 func (c *DefaultCache) PutUser(user User) {
 	if !c.flags.Has(CacheFlagUsers) {
 		return
@@ -405,6 +452,17 @@ func (c *DefaultCache) PutRole(role Role) {
 	c.guildToRoleIDs.Add(guildID, roleID)
 }
 
+func (c *DefaultCache) PutSoundboardSound(sound SoundBoardSound) {
+	if !c.flags.Has(CacheFlagSoundboardSounds) {
+		return
+	}
+	guildID := sound.GuildID
+	soundID := sound.SoundID
+	key := SnowflakePairKey{A: guildID, B: soundID}
+	c.soundsCache.Set(key, sound)
+	c.guildToSoundIDs.Add(guildID, soundID)
+}
+
 func (c *DefaultCache) DelUser(userID Snowflake) bool {
 	return c.usersCache.Delete(userID)
 }
@@ -477,3 +535,24 @@ func (c *DefaultCache) DelGuildMembers(guildID Snowflake) bool {
 	}
 	return true
 }
+
+func (c *DefaultCache) DelSoundboardSound(guildID, soundID Snowflake) bool {
+	key := SnowflakePairKey{A: guildID, B: soundID}
+	ok := c.soundsCache.Delete(key)
+	if ok {
+		c.guildToSoundIDs.Remove(guildID, soundID)
+	}
+	return ok
+}
+
+func (c *DefaultCache) DelGuildSoundboardSounds(guildID Snowflake) bool {
+	set, ok := c.guildToSoundIDs.Delete(guildID)
+	if !ok {
+		return false
+	}
+	for soundID := range set {
+		key := SnowflakePairKey{A: guildID, B: soundID}
+		c.soundsCache.Delete(key)
+	}
+	return true
+}
@@ -14,6 +14,7 @@
 package goda
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"time"
@@ -1076,41 +1077,56 @@ func UnmarshalChannel(buf []byte) (Channel, error) {
 		return nil, err
 	}
 
-	switch meta.Type {
+	c := channelOfType(meta.Type)
+	if c == nil {
+		return nil, errors.New("unknown channel type")
+	}
+	return c, json.Unmarshal(buf, c)
+}
+
+// unmarshalChannelETF is the ETF counterpart of UnmarshalChannel.
+func unmarshalChannelETF(d *etfDecoder) (Channel, error) {
+	var meta struct {
+		Type ChannelType `json:"type"`
+	}
+	if err := d.peek(&meta); err != nil {
+		return nil, err
+	}
+
+	c := channelOfType(meta.Type)
+	if c == nil {
+		return nil, errors.New("unknown channel type")
+	}
+	return c, d.unmarshal(c)
+}
+
+// channelOfType returns a new channel of the given type, or nil if the type is unknown.
+func channelOfType(channelType ChannelType) Channel {
+	switch channelType {
 	case ChannelTypeGuildCategory:
-		var c CategoryChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &CategoryChannel{}
 	case ChannelTypeGuildText:
-		var c TextChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &TextChannel{}
 	case ChannelTypeGuildVoice:
-		var c VoiceChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &VoiceChannel{}
 	case ChannelTypeGuildAnnouncement:
-		var c AnnouncementChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &AnnouncementChannel{}
 	case ChannelTypeGuildStageVoice:
-		var c StageVoiceChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &StageVoiceChannel{}
 	case ChannelTypeGuildForum:
-		var c ForumChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &ForumChannel{}
 	case ChannelTypeGuildMedia:
-		var c MediaChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &MediaChannel{}
 	case ChannelTypeAnnouncementThread,
 		ChannelTypePrivateThread,
 		ChannelTypePublicThread:
-		var c ThreadChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &ThreadChannel{}
 	case ChannelTypeDM:
-		var c DMChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &DMChannel{}
 	case ChannelTypeGroupDM:
-		var c GroupDMChannel
-		return &c, json.Unmarshal(buf, &c)
+		return &GroupDMChannel{}
 	default:
-		return nil, errors.New("unknown channel type")
+		return nil
 	}
 }
 
@@ -1140,6 +1156,26 @@ func (c *ResolvedChannel) UnmarshalJSON(buf []byte) error {
 	return nil
 }
 
+var _ etfUnmarshaler = (*ResolvedChannel)(nil)
+
+func (c *ResolvedChannel) unmarshalETF(d *etfDecoder) error {
+	var t struct {
+		Permissions Permissions `json:"permissions"`
+	}
+	if err := d.peek(&t); err != nil {
+		return err
+	}
+	c.Permissions = t.Permissions
+
+	channel, err := unmarshalChannelETF(d)
+	if err != nil {
+		return err
+	}
+	c.Channel = channel
+
+	return nil
+}
+
 type ResolvedMessageChannel struct {
 	MessageChannel
 	Permissions Permissions `json:"permissions"`
@@ -1170,6 +1206,30 @@ func (c *ResolvedMessageChannel) UnmarshalJSON(buf []byte) error {
 	return nil
 }
 
+var _ etfUnmarshaler = (*ResolvedMessageChannel)(nil)
+
+func (c *ResolvedMessageChannel) unmarshalETF(d *etfDecoder) error {
+	var t struct {
+		Permissions Permissions `json:"permissions"`
+	}
+	if err := d.peek(&t); err != nil {
+		return err
+	}
+	c.Permissions = t.Permissions
+
+	channel, err := unmarshalChannelETF(d)
+	if err != nil {
+		return err
+	}
+	if messageCh, ok := channel.(MessageChannel); ok {
+		c.MessageChannel = messageCh
+	} else {
+		return errors.New("cannot unmarshal non-MessageChannel into ResolvedMessageChannel")
+	}
+
+	return nil
+}
+
 type ResolvedThread struct {
 	ThreadChannel
 	Permissions Permissions `json:"permissions"`
@@ -1564,6 +1624,14 @@ func (c *VoiceChannel) Guild() (Guild, bool) {
 	return c.client.CacheManager.GetGuild(c.GuildID)
 }
 
+// Join joins this voice channel, see Client.JoinVoiceChannel.
+func (c *VoiceChannel) Join(ctx context.Context, mute, deaf bool) (VoiceSession, error) {
+	if c.client == nil {
+		return VoiceSession{}, ErrNoClient
+	}
+	return c.client.JoinVoiceChannel(ctx, c.GuildID, c.ID, mute, deaf)
+}
+
 /*****************************
  *  AnnouncementChannel Action Methods
  *****************************/
@@ -1752,6 +1820,14 @@ func (c *StageVoiceChannel) Guild() (Guild, bool) {
 	return c.client.CacheManager.GetGuild(c.GuildID)
 }
 
+// Join joins this stage channel as an audience member, see Client.JoinVoiceChannel.
+func (c *StageVoiceChannel) Join(ctx context.Context, mute, deaf bool) (VoiceSession, error) {
+	if c.client == nil {
+		return VoiceSession{}, ErrNoClient
+	}
+	return c.client.JoinVoiceChannel(ctx, c.GuildID, c.ID, mute, deaf)
+}
+
 /*****************************
  *  ThreadChannel Action Methods
  *****************************/
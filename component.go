@@ -1303,57 +1303,66 @@ func UnmarshalComponent(buf []byte) (Component, error) {
 		return nil, err
 	}
 
-	switch meta.Type {
+	c := componentOfType(meta.Type)
+	if c == nil {
+		return nil, errors.New("unknown component type")
+	}
+	return c, json.Unmarshal(buf, c)
+}
+
+// unmarshalComponentETF is the ETF counterpart of UnmarshalComponent.
+func unmarshalComponentETF(d *etfDecoder) (Component, error) {
+	var meta struct {
+		Type ComponentType `json:"type"`
+	}
+	if err := d.peek(&meta); err != nil {
+		return nil, err
+	}
+
+	c := componentOfType(meta.Type)
+	if c == nil {
+		return nil, errors.New("unknown component type")
+	}
+	return c, d.unmarshal(c)
+}
+
+// componentOfType returns a new component of the given type, or nil if the type is unknown.
+func componentOfType(componentType ComponentType) Component {
+	switch componentType {
 	case ComponentTypeActionRow:
-		var c ActionRowComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &ActionRowComponent{}
 	case ComponentTypeButton:
-		var c ButtonComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &ButtonComponent{}
 	case ComponentTypeStringSelect:
-		var c StringSelectMenuComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &StringSelectMenuComponent{}
 	case ComponentTypeTextInput:
-		var c TextInputComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &TextInputComponent{}
 	case ComponentTypeUserSelect:
-		var c UserSelectMenuComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &UserSelectMenuComponent{}
 	case ComponentTypeRoleSelect:
-		var c RoleSelectMenuComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &RoleSelectMenuComponent{}
 	case ComponentTypeMentionableSelect:
-		var c MentionableSelectMenuComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &MentionableSelectMenuComponent{}
 	case ComponentTypeChannelSelect:
-		var c ChannelSelectMenuComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &ChannelSelectMenuComponent{}
 	case ComponentTypeSection:
-		var c SectionComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &SectionComponent{}
 	case ComponentTypeTextDisplay:
-		var c TextDisplayComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &TextDisplayComponent{}
 	case ComponentTypeThumbnail:
-		var c ThumbnailComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &ThumbnailComponent{}
 	case ComponentTypeMediaGallery:
-		var c MediaGalleryComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &MediaGalleryComponent{}
 	case ComponentTypeFile:
-		var c FileComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &FileComponent{}
 	case ComponentTypeSeparator:
-		var c SeparatorComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &SeparatorComponent{}
 	case ComponentTypeContainer:
-		var c ContainerComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &ContainerComponent{}
 	case ComponentTypeLabel:
-		var c LabelComponent
-		return &c, json.Unmarshal(buf, &c)
+		return &LabelComponent{}
 	default:
-		return nil, errors.New("unknown component type")
+		return nil
 	}
 }
 
@@ -73,6 +73,12 @@ func newDispatcher(logger Logger, workerPool WorkerPool, cacheManager CacheManag
 	// Register some necessary events for caching
 	d.handlersManagers["READY"] = &readyHandlers{logger: logger}
 	d.handlersManagers["GUILD_CREATE"] = &guildCreateHandlers{logger: logger}
+	d.handlersManagers["GUILD_MEMBERS_CHUNK"] = &guildMembersChunkHandlers{logger: logger}
+	d.handlersManagers["GUILD_SOUNDBOARD_SOUND_CREATE"] = &guildSoundboardSoundCreateHandlers{logger: logger}
+	d.handlersManagers["GUILD_SOUNDBOARD_SOUND_UPDATE"] = &guildSoundboardSoundUpdateHandlers{logger: logger}
+	d.handlersManagers["GUILD_SOUNDBOARD_SOUND_DELETE"] = &guildSoundboardSoundDeleteHandlers{logger: logger}
+	d.handlersManagers["GUILD_SOUNDBOARD_SOUNDS_UPDATE"] = &guildSoundboardSoundsUpdateHandlers{logger: logger}
+	d.handlersManagers["SOUNDBOARD_SOUNDS"] = &soundboardSoundsHandlers{logger: logger}
 
 	return d
 }
@@ -87,6 +93,31 @@ func newDispatcher(logger Logger, workerPool WorkerPool, cacheManager CacheManag
 //
 // This method spawns a new goroutine for each dispatch to avoid blocking the main event loop.
 func (d *dispatcher) dispatch(shardID int, eventName string, data []byte) {
+	d.submit(shardID, eventName, func(hm eventhandlersManager) {
+		hm.handleEvent(d.cacheManager, shardID, data)
+	})
+}
+
+// dispatchGuildCreate is dispatch for GUILD_CREATE events,
+// joined reports if the bot joined the guild rather than the guild becoming available.
+func (d *dispatcher) dispatchGuildCreate(shardID int, data []byte, joined bool) {
+	d.submit(shardID, "GUILD_CREATE", func(hm eventhandlersManager) {
+		hm.(*guildCreateHandlers).handleGuildCreate(d.cacheManager, shardID, data, joined)
+	})
+}
+
+// dispatchLocal sends an event generated by goda to the handlers registered for it.
+//
+// Like Gateway events, handlers are called asynchronously through the worker pool.
+func dispatchLocal[T any](d *dispatcher, shardID int, eventName string, evt T) {
+	d.submit(shardID, eventName, func(hm eventhandlersManager) {
+		hm.(*localEventHandlers[T]).emit(evt)
+	})
+}
+
+// submit runs handle with the handlers manager of eventName, if any, through the worker pool,
+// recovering from panics of the handlers.
+func (d *dispatcher) submit(shardID int, eventName string, handle func(hm eventhandlersManager)) {
 	d.logger.Debug("Event '" + eventName + "' dispatched")
 	if !d.workerPool.Submit(func() {
 		defer func() {
@@ -104,7 +135,7 @@ func (d *dispatcher) dispatch(shardID int, eventName string, data []byte) {
 		d.mu.RUnlock()
 
 		if ok {
-			hm.handleEvent(d.cacheManager, shardID, data)
+			handle(hm)
 		}
 	}) {
 		d.logger.Warn("Dispatcher: dropped event '" + eventName + "' due to full queue")
@@ -225,4 +256,301 @@ func (d *dispatcher) OnVoiceStateUpdate(h func(VoiceStateUpdateEvent)) {
 	hm.addHandler(h)
 }
 
+// OnVoiceServerUpdate registers a handler function for 'VOICE_SERVER_UPDATE' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnVoiceServerUpdate(h func(VoiceServerUpdateEvent)) {
+	const key = "VOICE_SERVER_UPDATE" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	hm, ok := d.handlersManagers[key]
+	if !ok {
+		hm = &voiceServerUpdateHandlers{logger: d.logger}
+		d.handlersManagers[key] = hm
+	}
+	hm.addHandler(h)
+}
+
+// OnReady registers a handler function for 'READY' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnReady(h func(ReadyEvent)) {
+	const key = "READY" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// OnGuildCreate registers a handler function for 'GUILD_CREATE' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnGuildCreate(h func(GuildCreateEvent)) {
+	const key = "GUILD_CREATE" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// OnGuildMembersChunk registers a handler function for 'GUILD_MEMBERS_CHUNK' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnGuildMembersChunk(h func(GuildMembersChunkEvent)) {
+	const key = "GUILD_MEMBERS_CHUNK" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// guildMembersChunk returns the GUILD_MEMBERS_CHUNK handlers manager,
+// which tracks pending member requests.
+func (d *dispatcher) guildMembersChunk() *guildMembersChunkHandlers {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.handlersManagers["GUILD_MEMBERS_CHUNK"].(*guildMembersChunkHandlers)
+}
+
+// OnGuildSoundboardSoundCreate registers a handler function for 'GUILD_SOUNDBOARD_SOUND_CREATE' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnGuildSoundboardSoundCreate(h func(GuildSoundboardSoundCreateEvent)) {
+	const key = "GUILD_SOUNDBOARD_SOUND_CREATE" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// OnGuildSoundboardSoundUpdate registers a handler function for 'GUILD_SOUNDBOARD_SOUND_UPDATE' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnGuildSoundboardSoundUpdate(h func(GuildSoundboardSoundUpdateEvent)) {
+	const key = "GUILD_SOUNDBOARD_SOUND_UPDATE" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// OnGuildSoundboardSoundDelete registers a handler function for 'GUILD_SOUNDBOARD_SOUND_DELETE' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnGuildSoundboardSoundDelete(h func(GuildSoundboardSoundDeleteEvent)) {
+	const key = "GUILD_SOUNDBOARD_SOUND_DELETE" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// OnGuildSoundboardSoundsUpdate registers a handler function for 'GUILD_SOUNDBOARD_SOUNDS_UPDATE' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnGuildSoundboardSoundsUpdate(h func(GuildSoundboardSoundsUpdateEvent)) {
+	const key = "GUILD_SOUNDBOARD_SOUNDS_UPDATE" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// OnSoundboardSounds registers a handler function for 'SOUNDBOARD_SOUNDS' events.
+//
+// Note:
+//   - This method is thread-safe via internal locking.
+//   - However, it is strongly recommended to register all event handlers sequentially during startup,
+//     before starting event dispatching, to avoid runtime mutations and ensure stable configuration.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnSoundboardSounds(h func(SoundboardSoundsEvent)) {
+	const key = "SOUNDBOARD_SOUNDS" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlersManagers[key].addHandler(h)
+}
+
+// soundboardSounds returns the SOUNDBOARD_SOUNDS handlers manager,
+// which tracks pending soundboard sounds requests.
+func (d *dispatcher) soundboardSounds() *soundboardSoundsHandlers {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.handlersManagers["SOUNDBOARD_SOUNDS"].(*soundboardSoundsHandlers)
+}
+
+// OnShardError registers a handler function for shards stopping on an unrecoverable error,
+// such as an invalid token or disallowed intents.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnShardError(h func(ShardErrorEvent)) {
+	const key = "SHARD_ERROR" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	addLocalHandler(d, key, h)
+}
+
+// OnShardReady registers a handler function for 'SHARD_READY' events.
+//
+// A shard is ready once it received every guild listed in READY, or after no guild
+// arrived for the shard ready timeout (see WithShardReadyTimeout). Resumed sessions are ready on RESUMED.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnShardReady(h func(ShardReadyEvent)) {
+	const key = "SHARD_READY" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	addLocalHandler(d, key, h)
+}
+
+// OnClientReady registers a handler function for 'CLIENT_READY' events,
+// dispatched once when every shard of the client is ready for the first time.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnClientReady(h func(ClientReadyEvent)) {
+	const key = "CLIENT_READY" // event name
+	d.logger.Debug(key + " event handler registered")
+
+	addLocalHandler(d, key, h)
+}
+
+// OnShardConnect registers a handler function for 'SHARD_CONNECT' events,
+// dispatched when a shard opens a Gateway connection.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnShardConnect(h func(ShardConnectEvent)) {
+	const key = "SHARD_CONNECT" // event name
+	d.logger.Debug(key + " event handler registered")
+	addLocalHandler(d, key, h)
+}
+
+// OnShardDisconnect registers a handler function for 'SHARD_DISCONNECT' events,
+// dispatched when a shard loses or closes its Gateway connection.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnShardDisconnect(h func(ShardDisconnectEvent)) {
+	const key = "SHARD_DISCONNECT" // event name
+	d.logger.Debug(key + " event handler registered")
+	addLocalHandler(d, key, h)
+}
+
+// OnShardReconnecting registers a handler function for 'SHARD_RECONNECTING' events,
+// dispatched before each reconnect attempt of a shard.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnShardReconnecting(h func(ShardReconnectingEvent)) {
+	const key = "SHARD_RECONNECTING" // event name
+	d.logger.Debug(key + " event handler registered")
+	addLocalHandler(d, key, h)
+}
+
+// OnShardResumed registers a handler function for 'SHARD_RESUMED' events,
+// dispatched when a shard resumed its session.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnShardResumed(h func(ShardResumedEvent)) {
+	const key = "SHARD_RESUMED" // event name
+	d.logger.Debug(key + " event handler registered")
+	addLocalHandler(d, key, h)
+}
+
+// OnInvalidSession registers a handler function for 'INVALID_SESSION' events,
+// dispatched when the Gateway invalidates the session of a shard.
+//
+// Note:
+//   - This event is generated by goda, not received from the Gateway.
+//   - This method is thread-safe via internal locking.
+//   - Handlers are called sequentially when dispatching in the order they were added.
+func (d *dispatcher) OnInvalidSession(h func(InvalidSessionEvent)) {
+	const key = "INVALID_SESSION" // event name
+	d.logger.Debug(key + " event handler registered")
+	addLocalHandler(d, key, h)
+}
+
+// addLocalHandler registers a handler of an event generated by goda,
+// creating its handlers manager on first use.
+func addLocalHandler[T any](d *dispatcher, key string, h func(T)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	hm, ok := d.handlersManagers[key]
+	if !ok {
+		hm = &localEventHandlers[T]{}
+		d.handlersManagers[key] = hm
+	}
+	hm.addHandler(h)
+}
+
 // TODO: Add other OnXXX methods to register handlers for additional Discord events.
@@ -19,6 +19,13 @@ package goda
 // Entities embedding EntityBase can call action methods like Reply(), Delete(), etc.
 // The client reference is set automatically when entities are received from events
 // or fetched from the API.
+//
+// Action methods run with context.Background. To cancel them or bound them with a
+// deadline, call the matching REST method on a client copy carrying the context:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+//	defer cancel()
+//	err := message.Client().WithContext(ctx).DeleteMessage(message.ChannelID, message.ID, "")
 type EntityBase struct {
 	client *Client
 }
@@ -0,0 +1,946 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+// Code generated by gen_error_codes.go from the Discord API documentation. DO NOT EDIT.
+
+package goda
+
+import "strconv"
+
+// JSONErrorCode is a Discord JSON error code, returned in the "code" field
+// of the body of failing REST API responses.
+//
+// Every code is also an error matching the DiscordAPIError carrying it through errors.Is.
+//
+// Usage example:
+//
+//	err := client.DeleteMessage(channelID, messageID, "")
+//	if errors.Is(err, goda.JSONErrorCodeUnknownMessage) {
+//	    // the message was already deleted
+//	}
+//
+// Reference: https://discord.com/developers/docs/topics/opcodes-and-status-codes#json-json-error-codes
+//
+//go:generate go run gen_error_codes.go
+type JSONErrorCode int
+
+const (
+	// JSONErrorCodeGeneralError is the "General error (such as a malformed request body, amongst other things)" error.
+	JSONErrorCodeGeneralError JSONErrorCode = 0
+
+	// JSONErrorCodeUnknownAccount is the "Unknown account" error.
+	JSONErrorCodeUnknownAccount JSONErrorCode = 10001
+
+	// JSONErrorCodeUnknownApplication is the "Unknown application" error.
+	JSONErrorCodeUnknownApplication JSONErrorCode = 10002
+
+	// JSONErrorCodeUnknownChannel is the "Unknown channel" error.
+	JSONErrorCodeUnknownChannel JSONErrorCode = 10003
+
+	// JSONErrorCodeUnknownGuild is the "Unknown guild" error.
+	JSONErrorCodeUnknownGuild JSONErrorCode = 10004
+
+	// JSONErrorCodeUnknownIntegration is the "Unknown integration" error.
+	JSONErrorCodeUnknownIntegration JSONErrorCode = 10005
+
+	// JSONErrorCodeUnknownInvite is the "Unknown invite" error.
+	JSONErrorCodeUnknownInvite JSONErrorCode = 10006
+
+	// JSONErrorCodeUnknownMember is the "Unknown member" error.
+	JSONErrorCodeUnknownMember JSONErrorCode = 10007
+
+	// JSONErrorCodeUnknownMessage is the "Unknown message" error.
+	JSONErrorCodeUnknownMessage JSONErrorCode = 10008
+
+	// JSONErrorCodeUnknownPermissionOverwrite is the "Unknown permission overwrite" error.
+	JSONErrorCodeUnknownPermissionOverwrite JSONErrorCode = 10009
+
+	// JSONErrorCodeUnknownProvider is the "Unknown provider" error.
+	JSONErrorCodeUnknownProvider JSONErrorCode = 10010
+
+	// JSONErrorCodeUnknownRole is the "Unknown role" error.
+	JSONErrorCodeUnknownRole JSONErrorCode = 10011
+
+	// JSONErrorCodeUnknownToken is the "Unknown token" error.
+	JSONErrorCodeUnknownToken JSONErrorCode = 10012
+
+	// JSONErrorCodeUnknownUser is the "Unknown user" error.
+	JSONErrorCodeUnknownUser JSONErrorCode = 10013
+
+	// JSONErrorCodeUnknownEmoji is the "Unknown emoji" error.
+	JSONErrorCodeUnknownEmoji JSONErrorCode = 10014
+
+	// JSONErrorCodeUnknownWebhook is the "Unknown webhook" error.
+	JSONErrorCodeUnknownWebhook JSONErrorCode = 10015
+
+	// JSONErrorCodeUnknownWebhookService is the "Unknown webhook service" error.
+	JSONErrorCodeUnknownWebhookService JSONErrorCode = 10016
+
+	// JSONErrorCodeUnknownSession is the "Unknown session" error.
+	JSONErrorCodeUnknownSession JSONErrorCode = 10020
+
+	// JSONErrorCodeUnknownAsset is the "Unknown asset" error.
+	JSONErrorCodeUnknownAsset JSONErrorCode = 10021
+
+	// JSONErrorCodeUnknownBan is the "Unknown ban" error.
+	JSONErrorCodeUnknownBan JSONErrorCode = 10026
+
+	// JSONErrorCodeUnknownSKU is the "Unknown SKU" error.
+	JSONErrorCodeUnknownSKU JSONErrorCode = 10027
+
+	// JSONErrorCodeUnknownStoreListing is the "Unknown store listing" error.
+	JSONErrorCodeUnknownStoreListing JSONErrorCode = 10028
+
+	// JSONErrorCodeUnknownEntitlement is the "Unknown entitlement" error.
+	JSONErrorCodeUnknownEntitlement JSONErrorCode = 10029
+
+	// JSONErrorCodeUnknownBuild is the "Unknown build" error.
+	JSONErrorCodeUnknownBuild JSONErrorCode = 10030
+
+	// JSONErrorCodeUnknownLobby is the "Unknown lobby" error.
+	JSONErrorCodeUnknownLobby JSONErrorCode = 10031
+
+	// JSONErrorCodeUnknownBranch is the "Unknown branch" error.
+	JSONErrorCodeUnknownBranch JSONErrorCode = 10032
+
+	// JSONErrorCodeUnknownStoreDirectoryLayout is the "Unknown store directory layout" error.
+	JSONErrorCodeUnknownStoreDirectoryLayout JSONErrorCode = 10033
+
+	// JSONErrorCodeUnknownRedistributable is the "Unknown redistributable" error.
+	JSONErrorCodeUnknownRedistributable JSONErrorCode = 10036
+
+	// JSONErrorCodeUnknownGiftCode is the "Unknown gift code" error.
+	JSONErrorCodeUnknownGiftCode JSONErrorCode = 10038
+
+	// JSONErrorCodeUnknownStream is the "Unknown stream" error.
+	JSONErrorCodeUnknownStream JSONErrorCode = 10049
+
+	// JSONErrorCodeUnknownPremiumServerSubscribeCooldown is the "Unknown premium server subscribe cooldown" error.
+	JSONErrorCodeUnknownPremiumServerSubscribeCooldown JSONErrorCode = 10050
+
+	// JSONErrorCodeUnknownGuildTemplate is the "Unknown guild template" error.
+	JSONErrorCodeUnknownGuildTemplate JSONErrorCode = 10057
+
+	// JSONErrorCodeUnknownDiscoverableServerCategory is the "Unknown discoverable server category" error.
+	JSONErrorCodeUnknownDiscoverableServerCategory JSONErrorCode = 10059
+
+	// JSONErrorCodeUnknownSticker is the "Unknown sticker" error.
+	JSONErrorCodeUnknownSticker JSONErrorCode = 10060
+
+	// JSONErrorCodeUnknownStickerPack is the "Unknown sticker pack" error.
+	JSONErrorCodeUnknownStickerPack JSONErrorCode = 10061
+
+	// JSONErrorCodeUnknownInteraction is the "Unknown interaction" error.
+	JSONErrorCodeUnknownInteraction JSONErrorCode = 10062
+
+	// JSONErrorCodeUnknownApplicationCommand is the "Unknown application command" error.
+	JSONErrorCodeUnknownApplicationCommand JSONErrorCode = 10063
+
+	// JSONErrorCodeUnknownVoiceState is the "Unknown voice state" error.
+	JSONErrorCodeUnknownVoiceState JSONErrorCode = 10065
+
+	// JSONErrorCodeUnknownApplicationCommandPermissions is the "Unknown application command permissions" error.
+	JSONErrorCodeUnknownApplicationCommandPermissions JSONErrorCode = 10066
+
+	// JSONErrorCodeUnknownStageInstance is the "Unknown Stage Instance" error.
+	JSONErrorCodeUnknownStageInstance JSONErrorCode = 10067
+
+	// JSONErrorCodeUnknownGuildMemberVerificationForm is the "Unknown Guild Member Verification Form" error.
+	JSONErrorCodeUnknownGuildMemberVerificationForm JSONErrorCode = 10068
+
+	// JSONErrorCodeUnknownGuildWelcomeScreen is the "Unknown Guild Welcome Screen" error.
+	JSONErrorCodeUnknownGuildWelcomeScreen JSONErrorCode = 10069
+
+	// JSONErrorCodeUnknownGuildScheduledEvent is the "Unknown Guild Scheduled Event" error.
+	JSONErrorCodeUnknownGuildScheduledEvent JSONErrorCode = 10070
+
+	// JSONErrorCodeUnknownGuildScheduledEventUser is the "Unknown Guild Scheduled Event User" error.
+	JSONErrorCodeUnknownGuildScheduledEventUser JSONErrorCode = 10071
+
+	// JSONErrorCodeUnknownTag is the "Unknown Tag" error.
+	JSONErrorCodeUnknownTag JSONErrorCode = 10087
+
+	// JSONErrorCodeUnknownSound is the "Unknown sound" error.
+	JSONErrorCodeUnknownSound JSONErrorCode = 10097
+
+	// JSONErrorCodeBotsCannotUseThisEndpoint is the "Bots cannot use this endpoint" error.
+	JSONErrorCodeBotsCannotUseThisEndpoint JSONErrorCode = 20001
+
+	// JSONErrorCodeOnlyBotsCanUseThisEndpoint is the "Only bots can use this endpoint" error.
+	JSONErrorCodeOnlyBotsCanUseThisEndpoint JSONErrorCode = 20002
+
+	// JSONErrorCodeExplicitContentCannotBeSent is the "Explicit content cannot be sent to the desired recipient(s)" error.
+	JSONErrorCodeExplicitContentCannotBeSent JSONErrorCode = 20009
+
+	// JSONErrorCodeNotAuthorizedOnApplication is the "You are not authorized to perform this action on this application" error.
+	JSONErrorCodeNotAuthorizedOnApplication JSONErrorCode = 20012
+
+	// JSONErrorCodeSlowmodeRateLimit is the "This action cannot be performed due to slowmode rate limit" error.
+	JSONErrorCodeSlowmodeRateLimit JSONErrorCode = 20016
+
+	// JSONErrorCodeOnlyAccountOwner is the "Only the owner of this account can perform this action" error.
+	JSONErrorCodeOnlyAccountOwner JSONErrorCode = 20018
+
+	// JSONErrorCodeAnnouncementRateLimit is the "This message cannot be edited due to announcement rate limits" error.
+	JSONErrorCodeAnnouncementRateLimit JSONErrorCode = 20022
+
+	// JSONErrorCodeUnderMinimumAge is the "Under minimum age" error.
+	JSONErrorCodeUnderMinimumAge JSONErrorCode = 20024
+
+	// JSONErrorCodeChannelWriteRateLimit is the "The channel you are writing has hit the write rate limit" error.
+	JSONErrorCodeChannelWriteRateLimit JSONErrorCode = 20028
+
+	// JSONErrorCodeServerWriteRateLimit is the "The write action you are performing on the server has hit the write rate limit" error.
+	JSONErrorCodeServerWriteRateLimit JSONErrorCode = 20029
+
+	// JSONErrorCodeWordsNotAllowed is the "Your Stage topic, server name, server description, or channel names contain words that are not allowed" error.
+	JSONErrorCodeWordsNotAllowed JSONErrorCode = 20031
+
+	// JSONErrorCodeGuildPremiumLevelTooLow is the "Guild premium subscription level too low" error.
+	JSONErrorCodeGuildPremiumLevelTooLow JSONErrorCode = 20035
+
+	// JSONErrorCodeMaxGuilds is the "Maximum number of guilds reached (100)" error.
+	JSONErrorCodeMaxGuilds JSONErrorCode = 30001
+
+	// JSONErrorCodeMaxFriends is the "Maximum number of friends reached (1000)" error.
+	JSONErrorCodeMaxFriends JSONErrorCode = 30002
+
+	// JSONErrorCodeMaxPins is the "Maximum number of pins reached for the channel (50)" error.
+	JSONErrorCodeMaxPins JSONErrorCode = 30003
+
+	// JSONErrorCodeMaxRecipients is the "Maximum number of recipients reached (10)" error.
+	JSONErrorCodeMaxRecipients JSONErrorCode = 30004
+
+	// JSONErrorCodeMaxGuildRoles is the "Maximum number of guild roles reached (250)" error.
+	JSONErrorCodeMaxGuildRoles JSONErrorCode = 30005
+
+	// JSONErrorCodeMaxWebhooks is the "Maximum number of webhooks reached (15)" error.
+	JSONErrorCodeMaxWebhooks JSONErrorCode = 30007
+
+	// JSONErrorCodeMaxEmojis is the "Maximum number of emojis reached" error.
+	JSONErrorCodeMaxEmojis JSONErrorCode = 30008
+
+	// JSONErrorCodeMaxReactions is the "Maximum number of reactions reached (20)" error.
+	JSONErrorCodeMaxReactions JSONErrorCode = 30010
+
+	// JSONErrorCodeMaxGroupDMs is the "Maximum number of group DMs reached (10)" error.
+	JSONErrorCodeMaxGroupDMs JSONErrorCode = 30011
+
+	// JSONErrorCodeMaxGuildChannels is the "Maximum number of guild channels reached (500)" error.
+	JSONErrorCodeMaxGuildChannels JSONErrorCode = 30013
+
+	// JSONErrorCodeMaxAttachments is the "Maximum number of attachments in a message reached (10)" error.
+	JSONErrorCodeMaxAttachments JSONErrorCode = 30015
+
+	// JSONErrorCodeMaxInvites is the "Maximum number of invites reached (1000)" error.
+	JSONErrorCodeMaxInvites JSONErrorCode = 30016
+
+	// JSONErrorCodeMaxAnimatedEmojis is the "Maximum number of animated emojis reached" error.
+	JSONErrorCodeMaxAnimatedEmojis JSONErrorCode = 30018
+
+	// JSONErrorCodeMaxServerMembers is the "Maximum number of server members reached" error.
+	JSONErrorCodeMaxServerMembers JSONErrorCode = 30019
+
+	// JSONErrorCodeMaxServerCategories is the "Maximum number of server categories has been reached (5)" error.
+	JSONErrorCodeMaxServerCategories JSONErrorCode = 30030
+
+	// JSONErrorCodeGuildAlreadyHasTemplate is the "Guild already has a template" error.
+	JSONErrorCodeGuildAlreadyHasTemplate JSONErrorCode = 30031
+
+	// JSONErrorCodeMaxApplicationCommands is the "Maximum number of application commands reached" error.
+	JSONErrorCodeMaxApplicationCommands JSONErrorCode = 30032
+
+	// JSONErrorCodeMaxThreadParticipants is the "Maximum number of thread participants has been reached (1000)" error.
+	JSONErrorCodeMaxThreadParticipants JSONErrorCode = 30033
+
+	// JSONErrorCodeMaxDailyApplicationCommandCreates is the "Maximum number of daily application command creates has been reached (200)" error.
+	JSONErrorCodeMaxDailyApplicationCommandCreates JSONErrorCode = 30034
+
+	// JSONErrorCodeMaxNonMemberBans is the "Maximum number of bans for non-guild members have been exceeded" error.
+	JSONErrorCodeMaxNonMemberBans JSONErrorCode = 30035
+
+	// JSONErrorCodeMaxBanFetches is the "Maximum number of bans fetches has been reached" error.
+	JSONErrorCodeMaxBanFetches JSONErrorCode = 30037
+
+	// JSONErrorCodeMaxUncompletedScheduledEvents is the "Maximum number of uncompleted guild scheduled events reached (100)" error.
+	JSONErrorCodeMaxUncompletedScheduledEvents JSONErrorCode = 30038
+
+	// JSONErrorCodeMaxStickers is the "Maximum number of stickers reached" error.
+	JSONErrorCodeMaxStickers JSONErrorCode = 30039
+
+	// JSONErrorCodeMaxPruneRequests is the "Maximum number of prune requests has been reached. Try again later" error.
+	JSONErrorCodeMaxPruneRequests JSONErrorCode = 30040
+
+	// JSONErrorCodeMaxGuildWidgetUpdates is the "Maximum number of guild widget settings updates has been reached. Try again later" error.
+	JSONErrorCodeMaxGuildWidgetUpdates JSONErrorCode = 30042
+
+	// JSONErrorCodeMaxSoundboardSounds is the "Maximum number of soundboard sounds reached" error.
+	JSONErrorCodeMaxSoundboardSounds JSONErrorCode = 30045
+
+	// JSONErrorCodeMaxOldMessageEdits is the "Maximum number of edits to messages older than 1 hour reached. Try again later" error.
+	JSONErrorCodeMaxOldMessageEdits JSONErrorCode = 30046
+
+	// JSONErrorCodeMaxPinnedForumThreads is the "Maximum number of pinned threads in a forum channel has been reached" error.
+	JSONErrorCodeMaxPinnedForumThreads JSONErrorCode = 30047
+
+	// JSONErrorCodeMaxForumTags is the "Maximum number of tags in a forum channel has been reached" error.
+	JSONErrorCodeMaxForumTags JSONErrorCode = 30048
+
+	// JSONErrorCodeBitrateTooHigh is the "Bitrate is too high for channel of this type" error.
+	JSONErrorCodeBitrateTooHigh JSONErrorCode = 30052
+
+	// JSONErrorCodeMaxPremiumEmojis is the "Maximum number of premium emojis reached (25)" error.
+	JSONErrorCodeMaxPremiumEmojis JSONErrorCode = 30056
+
+	// JSONErrorCodeMaxGuildWebhooks is the "Maximum number of webhooks per guild reached (1000)" error.
+	JSONErrorCodeMaxGuildWebhooks JSONErrorCode = 30058
+
+	// JSONErrorCodeMaxChannelPermissionOverwrites is the "Maximum number of channel permission overwrites reached (1000)" error.
+	JSONErrorCodeMaxChannelPermissionOverwrites JSONErrorCode = 30061
+
+	// JSONErrorCodeGuildChannelsTooLarge is the "The channels for this guild are too large" error.
+	JSONErrorCodeGuildChannelsTooLarge JSONErrorCode = 30062
+
+	// JSONErrorCodeUnauthorized is the "Unauthorized. Provide a valid token and try again" error.
+	JSONErrorCodeUnauthorized JSONErrorCode = 40001
+
+	// JSONErrorCodeAccountVerificationRequired is the "You need to verify your account in order to perform this action" error.
+	JSONErrorCodeAccountVerificationRequired JSONErrorCode = 40002
+
+	// JSONErrorCodeOpeningDMsTooFast is the "You are opening direct messages too fast" error.
+	JSONErrorCodeOpeningDMsTooFast JSONErrorCode = 40003
+
+	// JSONErrorCodeSendMessagesDisabled is the "Send messages has been temporarily disabled" error.
+	JSONErrorCodeSendMessagesDisabled JSONErrorCode = 40004
+
+	// JSONErrorCodeRequestEntityTooLarge is the "Request entity too large. Try sending something smaller in size" error.
+	JSONErrorCodeRequestEntityTooLarge JSONErrorCode = 40005
+
+	// JSONErrorCodeFeatureDisabled is the "This feature has been temporarily disabled server-side" error.
+	JSONErrorCodeFeatureDisabled JSONErrorCode = 40006
+
+	// JSONErrorCodeUserBannedFromGuild is the "The user is banned from this guild" error.
+	JSONErrorCodeUserBannedFromGuild JSONErrorCode = 40007
+
+	// JSONErrorCodeConnectionRevoked is the "Connection has been revoked" error.
+	JSONErrorCodeConnectionRevoked JSONErrorCode = 40012
+
+	// JSONErrorCodeOnlyConsumableSKUs is the "Only consumable SKUs can be consumed" error.
+	JSONErrorCodeOnlyConsumableSKUs JSONErrorCode = 40018
+
+	// JSONErrorCodeOnlySandboxEntitlements is the "You can only delete sandbox entitlements" error.
+	JSONErrorCodeOnlySandboxEntitlements JSONErrorCode = 40019
+
+	// JSONErrorCodeTargetUserNotInVoice is the "Target user is not connected to voice" error.
+	JSONErrorCodeTargetUserNotInVoice JSONErrorCode = 40032
+
+	// JSONErrorCodeMessageAlreadyCrossposted is the "This message has already been crossposted" error.
+	JSONErrorCodeMessageAlreadyCrossposted JSONErrorCode = 40033
+
+	// JSONErrorCodeApplicationCommandNameExists is the "An application command with that name already exists" error.
+	JSONErrorCodeApplicationCommandNameExists JSONErrorCode = 40041
+
+	// JSONErrorCodeInteractionFailedToSend is the "Application interaction failed to send" error.
+	JSONErrorCodeInteractionFailedToSend JSONErrorCode = 40043
+
+	// JSONErrorCodeCannotSendInForumChannel is the "Cannot send a message in a forum channel" error.
+	JSONErrorCodeCannotSendInForumChannel JSONErrorCode = 40058
+
+	// JSONErrorCodeInteractionAlreadyAcknowledged is the "Interaction has already been acknowledged" error.
+	JSONErrorCodeInteractionAlreadyAcknowledged JSONErrorCode = 40060
+
+	// JSONErrorCodeTagNamesMustBeUnique is the "Tag names must be unique" error.
+	JSONErrorCodeTagNamesMustBeUnique JSONErrorCode = 40061
+
+	// JSONErrorCodeServiceResourceRateLimited is the "Service resource is being rate limited" error.
+	JSONErrorCodeServiceResourceRateLimited JSONErrorCode = 40062
+
+	// JSONErrorCodeNoTagsForNonModerators is the "There are no tags available that can be set by non-moderators" error.
+	JSONErrorCodeNoTagsForNonModerators JSONErrorCode = 40066
+
+	// JSONErrorCodeTagRequiredForForumPost is the "A tag is required to create a forum post in this channel" error.
+	JSONErrorCodeTagRequiredForForumPost JSONErrorCode = 40067
+
+	// JSONErrorCodeEntitlementAlreadyGranted is the "An entitlement has already been granted for this resource" error.
+	JSONErrorCodeEntitlementAlreadyGranted JSONErrorCode = 40074
+
+	// JSONErrorCodeMaxFollowupMessages is the "This interaction has hit the maximum number of follow up messages" error.
+	JSONErrorCodeMaxFollowupMessages JSONErrorCode = 40094
+
+	// JSONErrorCodeCloudflareBlocked is the "Cloudflare is blocking your request. This can often be resolved by setting a proper User Agent" error.
+	JSONErrorCodeCloudflareBlocked JSONErrorCode = 40333
+
+	// JSONErrorCodeMissingAccess is the "Missing access" error.
+	JSONErrorCodeMissingAccess JSONErrorCode = 50001
+
+	// JSONErrorCodeInvalidAccountType is the "Invalid account type" error.
+	JSONErrorCodeInvalidAccountType JSONErrorCode = 50002
+
+	// JSONErrorCodeCannotExecuteOnDMChannel is the "Cannot execute action on a DM channel" error.
+	JSONErrorCodeCannotExecuteOnDMChannel JSONErrorCode = 50003
+
+	// JSONErrorCodeGuildWidgetDisabled is the "Guild widget disabled" error.
+	JSONErrorCodeGuildWidgetDisabled JSONErrorCode = 50004
+
+	// JSONErrorCodeCannotEditOtherUserMessage is the "Cannot edit a message authored by another user" error.
+	JSONErrorCodeCannotEditOtherUserMessage JSONErrorCode = 50005
+
+	// JSONErrorCodeCannotSendEmptyMessage is the "Cannot send an empty message" error.
+	JSONErrorCodeCannotSendEmptyMessage JSONErrorCode = 50006
+
+	// JSONErrorCodeCannotSendMessagesToUser is the "Cannot send messages to this user" error.
+	JSONErrorCodeCannotSendMessagesToUser JSONErrorCode = 50007
+
+	// JSONErrorCodeCannotSendMessagesInNonTextChannel is the "Cannot send messages in a non-text channel" error.
+	JSONErrorCodeCannotSendMessagesInNonTextChannel JSONErrorCode = 50008
+
+	// JSONErrorCodeChannelVerificationLevelTooHigh is the "Channel verification level is too high for you to gain access" error.
+	JSONErrorCodeChannelVerificationLevelTooHigh JSONErrorCode = 50009
+
+	// JSONErrorCodeOAuth2ApplicationHasNoBot is the "OAuth2 application does not have a bot" error.
+	JSONErrorCodeOAuth2ApplicationHasNoBot JSONErrorCode = 50010
+
+	// JSONErrorCodeOAuth2ApplicationLimitReached is the "OAuth2 application limit reached" error.
+	JSONErrorCodeOAuth2ApplicationLimitReached JSONErrorCode = 50011
+
+	// JSONErrorCodeInvalidOAuth2State is the "Invalid OAuth2 state" error.
+	JSONErrorCodeInvalidOAuth2State JSONErrorCode = 50012
+
+	// JSONErrorCodeMissingPermissions is the "You lack permissions to perform that action" error.
+	JSONErrorCodeMissingPermissions JSONErrorCode = 50013
+
+	// JSONErrorCodeInvalidAuthenticationToken is the "Invalid authentication token provided" error.
+	JSONErrorCodeInvalidAuthenticationToken JSONErrorCode = 50014
+
+	// JSONErrorCodeNoteTooLong is the "Note was too long" error.
+	JSONErrorCodeNoteTooLong JSONErrorCode = 50015
+
+	// JSONErrorCodeInvalidBulkDeleteCount is the "Provided too few or too many messages to delete. Must provide at least 2 and fewer than 100 messages to delete" error.
+	JSONErrorCodeInvalidBulkDeleteCount JSONErrorCode = 50016
+
+	// JSONErrorCodeInvalidMFALevel is the "Invalid MFA Level" error.
+	JSONErrorCodeInvalidMFALevel JSONErrorCode = 50017
+
+	// JSONErrorCodeCannotPinInOtherChannel is the "A message can only be pinned to the channel it was sent in" error.
+	JSONErrorCodeCannotPinInOtherChannel JSONErrorCode = 50019
+
+	// JSONErrorCodeInvalidInviteCode is the "Invite code was either invalid or taken" error.
+	JSONErrorCodeInvalidInviteCode JSONErrorCode = 50020
+
+	// JSONErrorCodeCannotExecuteOnSystemMessage is the "Cannot execute action on a system message" error.
+	JSONErrorCodeCannotExecuteOnSystemMessage JSONErrorCode = 50021
+
+	// JSONErrorCodeCannotExecuteOnChannelType is the "Cannot execute action on this channel type" error.
+	JSONErrorCodeCannotExecuteOnChannelType JSONErrorCode = 50024
+
+	// JSONErrorCodeInvalidOAuth2AccessToken is the "Invalid OAuth2 access token provided" error.
+	JSONErrorCodeInvalidOAuth2AccessToken JSONErrorCode = 50025
+
+	// JSONErrorCodeMissingOAuth2Scope is the "Missing required OAuth2 scope" error.
+	JSONErrorCodeMissingOAuth2Scope JSONErrorCode = 50026
+
+	// JSONErrorCodeInvalidWebhookToken is the "Invalid webhook token provided" error.
+	JSONErrorCodeInvalidWebhookToken JSONErrorCode = 50027
+
+	// JSONErrorCodeInvalidRole is the "Invalid role" error.
+	JSONErrorCodeInvalidRole JSONErrorCode = 50028
+
+	// JSONErrorCodeInvalidRecipients is the "Invalid Recipient(s)" error.
+	JSONErrorCodeInvalidRecipients JSONErrorCode = 50033
+
+	// JSONErrorCodeMessageTooOldToBulkDelete is the "A message provided was too old to bulk delete" error.
+	JSONErrorCodeMessageTooOldToBulkDelete JSONErrorCode = 50034
+
+	// JSONErrorCodeInvalidFormBody is the "Invalid form body (returned for both application/json and multipart/form-data bodies), or invalid Content-Type provided" error.
+	JSONErrorCodeInvalidFormBody JSONErrorCode = 50035
+
+	// JSONErrorCodeInviteAcceptedToGuildWithoutBot is the "An invite was accepted to a guild the application's bot is not in" error.
+	JSONErrorCodeInviteAcceptedToGuildWithoutBot JSONErrorCode = 50036
+
+	// JSONErrorCodeInvalidActivityAction is the "Invalid Activity Action" error.
+	JSONErrorCodeInvalidActivityAction JSONErrorCode = 50039
+
+	// JSONErrorCodeInvalidAPIVersion is the "Invalid API version provided" error.
+	JSONErrorCodeInvalidAPIVersion JSONErrorCode = 50041
+
+	// JSONErrorCodeFileTooLarge is the "File uploaded exceeds the maximum size" error.
+	JSONErrorCodeFileTooLarge JSONErrorCode = 50045
+
+	// JSONErrorCodeInvalidFileUploaded is the "Invalid file uploaded" error.
+	JSONErrorCodeInvalidFileUploaded JSONErrorCode = 50046
+
+	// JSONErrorCodeCannotSelfRedeemGift is the "Cannot self-redeem this gift" error.
+	JSONErrorCodeCannotSelfRedeemGift JSONErrorCode = 50054
+
+	// JSONErrorCodeInvalidGuild is the "Invalid Guild" error.
+	JSONErrorCodeInvalidGuild JSONErrorCode = 50055
+
+	// JSONErrorCodeInvalidSKU is the "Invalid SKU" error.
+	JSONErrorCodeInvalidSKU JSONErrorCode = 50057
+
+	// JSONErrorCodeInvalidRequestOrigin is the "Invalid request origin" error.
+	JSONErrorCodeInvalidRequestOrigin JSONErrorCode = 50067
+
+	// JSONErrorCodeInvalidMessageType is the "Invalid message type" error.
+	JSONErrorCodeInvalidMessageType JSONErrorCode = 50068
+
+	// JSONErrorCodePaymentSourceRequired is the "Payment source required to redeem gift" error.
+	JSONErrorCodePaymentSourceRequired JSONErrorCode = 50070
+
+	// JSONErrorCodeCannotModifySystemWebhook is the "Cannot modify a system webhook" error.
+	JSONErrorCodeCannotModifySystemWebhook JSONErrorCode = 50073
+
+	// JSONErrorCodeCannotDeleteCommunityChannel is the "Cannot delete a channel required for Community guilds" error.
+	JSONErrorCodeCannotDeleteCommunityChannel JSONErrorCode = 50074
+
+	// JSONErrorCodeCannotEditMessageStickers is the "Cannot edit stickers within a message" error.
+	JSONErrorCodeCannotEditMessageStickers JSONErrorCode = 50080
+
+	// JSONErrorCodeInvalidSticker is the "Invalid sticker sent" error.
+	JSONErrorCodeInvalidSticker JSONErrorCode = 50081
+
+	// JSONErrorCodeThreadArchived is the "Tried to perform an operation on an archived thread, such as editing a message or adding a user to the thread" error.
+	JSONErrorCodeThreadArchived JSONErrorCode = 50083
+
+	// JSONErrorCodeInvalidThreadNotificationSettings is the "Invalid thread notification settings" error.
+	JSONErrorCodeInvalidThreadNotificationSettings JSONErrorCode = 50084
+
+	// JSONErrorCodeBeforeEarlierThanThreadCreation is the "before value is earlier than the thread creation date" error.
+	JSONErrorCodeBeforeEarlierThanThreadCreation JSONErrorCode = 50085
+
+	// JSONErrorCodeCommunityChannelsMustBeText is the "Community server channels must be text channels" error.
+	JSONErrorCodeCommunityChannelsMustBeText JSONErrorCode = 50086
+
+	// JSONErrorCodeEventEntityTypeMismatch is the "The entity type of the event is different from the entity you are trying to start the event for" error.
+	JSONErrorCodeEventEntityTypeMismatch JSONErrorCode = 50091
+
+	// JSONErrorCodeServerNotAvailableInLocation is the "This server is not available in your location" error.
+	JSONErrorCodeServerNotAvailableInLocation JSONErrorCode = 50095
+
+	// JSONErrorCodeMonetizationRequired is the "This server needs monetization enabled in order to perform this action" error.
+	JSONErrorCodeMonetizationRequired JSONErrorCode = 50097
+
+	// JSONErrorCodeMoreBoostsRequired is the "This server needs more boosts to perform this action" error.
+	JSONErrorCodeMoreBoostsRequired JSONErrorCode = 50101
+
+	// JSONErrorCodeInvalidJSONBody is the "The request body contains invalid JSON" error.
+	JSONErrorCodeInvalidJSONBody JSONErrorCode = 50109
+
+	// JSONErrorCodeInvalidFile is the "The provided file is invalid" error.
+	JSONErrorCodeInvalidFile JSONErrorCode = 50110
+
+	// JSONErrorCodeInvalidFileType is the "The provided file type is invalid" error.
+	JSONErrorCodeInvalidFileType JSONErrorCode = 50123
+
+	// JSONErrorCodeFileDurationTooLong is the "The provided file duration exceeds maximum of 5.2 seconds" error.
+	JSONErrorCodeFileDurationTooLong JSONErrorCode = 50124
+
+	// JSONErrorCodeOwnerCannotBePendingMember is the "Owner cannot be pending member" error.
+	JSONErrorCodeOwnerCannotBePendingMember JSONErrorCode = 50131
+
+	// JSONErrorCodeCannotTransferOwnershipToBot is the "Ownership cannot be transferred to a bot user" error.
+	JSONErrorCodeCannotTransferOwnershipToBot JSONErrorCode = 50132
+
+	// JSONErrorCodeCannotResizeAsset is the "Failed to resize asset below the maximum size: 262144" error.
+	JSONErrorCodeCannotResizeAsset JSONErrorCode = 50138
+
+	// JSONErrorCodeCannotMixSubscriptionRoles is the "Cannot mix subscription and non subscription roles for an emoji" error.
+	JSONErrorCodeCannotMixSubscriptionRoles JSONErrorCode = 50144
+
+	// JSONErrorCodeCannotConvertPremiumEmoji is the "Cannot convert between premium emoji and normal emoji" error.
+	JSONErrorCodeCannotConvertPremiumEmoji JSONErrorCode = 50145
+
+	// JSONErrorCodeUploadedFileNotFound is the "Uploaded file not found" error.
+	JSONErrorCodeUploadedFileNotFound JSONErrorCode = 50146
+
+	// JSONErrorCodeInvalidEmoji is the "The specified emoji is invalid" error.
+	JSONErrorCodeInvalidEmoji JSONErrorCode = 50151
+
+	// JSONErrorCodeVoiceMessageAdditionalContent is the "Voice messages do not support additional content" error.
+	JSONErrorCodeVoiceMessageAdditionalContent JSONErrorCode = 50159
+
+	// JSONErrorCodeVoiceMessageSingleAttachment is the "Voice messages must have a single audio attachment" error.
+	JSONErrorCodeVoiceMessageSingleAttachment JSONErrorCode = 50160
+
+	// JSONErrorCodeVoiceMessageMetadataRequired is the "Voice messages must have supporting metadata" error.
+	JSONErrorCodeVoiceMessageMetadataRequired JSONErrorCode = 50161
+
+	// JSONErrorCodeVoiceMessageCannotBeEdited is the "Voice messages cannot be edited" error.
+	JSONErrorCodeVoiceMessageCannotBeEdited JSONErrorCode = 50162
+
+	// JSONErrorCodeCannotDeleteSubscriptionIntegration is the "Cannot delete guild subscription integration" error.
+	JSONErrorCodeCannotDeleteSubscriptionIntegration JSONErrorCode = 50163
+
+	// JSONErrorCodeCannotSendVoiceMessages is the "You cannot send voice messages in this channel" error.
+	JSONErrorCodeCannotSendVoiceMessages JSONErrorCode = 50173
+
+	// JSONErrorCodeAccountMustBeVerified is the "The user account must first be verified" error.
+	JSONErrorCodeAccountMustBeVerified JSONErrorCode = 50178
+
+	// JSONErrorCodeInvalidFileDuration is the "The provided file does not have a valid duration" error.
+	JSONErrorCodeInvalidFileDuration JSONErrorCode = 50192
+
+	// JSONErrorCodeCannotSendSticker is the "You do not have permission to send this sticker" error.
+	JSONErrorCodeCannotSendSticker JSONErrorCode = 50600
+
+	// JSONErrorCodeTwoFactorRequired is the "Two factor is required for this operation" error.
+	JSONErrorCodeTwoFactorRequired JSONErrorCode = 60003
+
+	// JSONErrorCodeNoUsersWithDiscordTag is the "No users with DiscordTag exist" error.
+	JSONErrorCodeNoUsersWithDiscordTag JSONErrorCode = 80004
+
+	// JSONErrorCodeReactionBlocked is the "Reaction was blocked" error.
+	JSONErrorCodeReactionBlocked JSONErrorCode = 90001
+
+	// JSONErrorCodeCannotUseBurstReactions is the "User cannot use burst reactions" error.
+	JSONErrorCodeCannotUseBurstReactions JSONErrorCode = 90002
+
+	// JSONErrorCodeApplicationNotAvailable is the "Application not yet available. Try again later" error.
+	JSONErrorCodeApplicationNotAvailable JSONErrorCode = 110001
+
+	// JSONErrorCodeAPIResourceOverloaded is the "API resource is currently overloaded. Try again a little later" error.
+	JSONErrorCodeAPIResourceOverloaded JSONErrorCode = 130000
+
+	// JSONErrorCodeStageAlreadyOpen is the "The Stage is already open" error.
+	JSONErrorCodeStageAlreadyOpen JSONErrorCode = 150006
+
+	// JSONErrorCodeCannotReplyWithoutReadHistory is the "Cannot reply without permission to read message history" error.
+	JSONErrorCodeCannotReplyWithoutReadHistory JSONErrorCode = 160002
+
+	// JSONErrorCodeThreadAlreadyCreated is the "A thread has already been created for this message" error.
+	JSONErrorCodeThreadAlreadyCreated JSONErrorCode = 160004
+
+	// JSONErrorCodeThreadLocked is the "Thread is locked" error.
+	JSONErrorCodeThreadLocked JSONErrorCode = 160005
+
+	// JSONErrorCodeMaxActiveThreads is the "Maximum number of active threads reached" error.
+	JSONErrorCodeMaxActiveThreads JSONErrorCode = 160006
+
+	// JSONErrorCodeMaxActiveAnnouncementThreads is the "Maximum number of active announcement threads reached" error.
+	JSONErrorCodeMaxActiveAnnouncementThreads JSONErrorCode = 160007
+
+	// JSONErrorCodeInvalidLottieJSON is the "Invalid JSON for uploaded Lottie file" error.
+	JSONErrorCodeInvalidLottieJSON JSONErrorCode = 170001
+
+	// JSONErrorCodeLottieRasterizedImages is the "Uploaded Lotties cannot contain rasterized images such as PNG or JPEG" error.
+	JSONErrorCodeLottieRasterizedImages JSONErrorCode = 170002
+
+	// JSONErrorCodeStickerMaxFramerateExceeded is the "Sticker maximum framerate exceeded" error.
+	JSONErrorCodeStickerMaxFramerateExceeded JSONErrorCode = 170003
+
+	// JSONErrorCodeStickerMaxFramesExceeded is the "Sticker frame count exceeds maximum of 1000 frames" error.
+	JSONErrorCodeStickerMaxFramesExceeded JSONErrorCode = 170004
+
+	// JSONErrorCodeLottieMaxDimensionsExceeded is the "Lottie animation maximum dimensions exceeded" error.
+	JSONErrorCodeLottieMaxDimensionsExceeded JSONErrorCode = 170005
+
+	// JSONErrorCodeStickerInvalidFramerate is the "Sticker frame rate is either too small or too large" error.
+	JSONErrorCodeStickerInvalidFramerate JSONErrorCode = 170006
+
+	// JSONErrorCodeStickerMaxDurationExceeded is the "Sticker animation duration exceeds maximum of 5 seconds" error.
+	JSONErrorCodeStickerMaxDurationExceeded JSONErrorCode = 170007
+
+	// JSONErrorCodeCannotUpdateFinishedEvent is the "Cannot update a finished event" error.
+	JSONErrorCodeCannotUpdateFinishedEvent JSONErrorCode = 180000
+
+	// JSONErrorCodeFailedToCreateStageForEvent is the "Failed to create stage needed for stage event" error.
+	JSONErrorCodeFailedToCreateStageForEvent JSONErrorCode = 180002
+
+	// JSONErrorCodeMessageBlockedByAutoMod is the "Message was blocked by automatic moderation" error.
+	JSONErrorCodeMessageBlockedByAutoMod JSONErrorCode = 200000
+
+	// JSONErrorCodeTitleBlockedByAutoMod is the "Title was blocked by automatic moderation" error.
+	JSONErrorCodeTitleBlockedByAutoMod JSONErrorCode = 200001
+
+	// JSONErrorCodeForumWebhookThreadRequired is the "Webhooks posted to forum channels must have a thread_name or thread_id" error.
+	JSONErrorCodeForumWebhookThreadRequired JSONErrorCode = 220001
+
+	// JSONErrorCodeForumWebhookThreadConflict is the "Webhooks posted to forum channels cannot have both a thread_name and thread_id" error.
+	JSONErrorCodeForumWebhookThreadConflict JSONErrorCode = 220002
+
+	// JSONErrorCodeWebhookThreadsOnlyInForums is the "Webhooks can only create threads in forum channels" error.
+	JSONErrorCodeWebhookThreadsOnlyInForums JSONErrorCode = 220003
+
+	// JSONErrorCodeWebhookServicesInForums is the "Webhook services cannot be used in forum channels" error.
+	JSONErrorCodeWebhookServicesInForums JSONErrorCode = 220004
+
+	// JSONErrorCodeMessageBlockedByLinkFilter is the "Message blocked by harmful links filter" error.
+	JSONErrorCodeMessageBlockedByLinkFilter JSONErrorCode = 240000
+
+	// JSONErrorCodeCannotEnableOnboarding is the "Cannot enable onboarding, requirements are not met" error.
+	JSONErrorCodeCannotEnableOnboarding JSONErrorCode = 350000
+
+	// JSONErrorCodeCannotUpdateOnboarding is the "Cannot update onboarding while below requirements" error.
+	JSONErrorCodeCannotUpdateOnboarding JSONErrorCode = 350001
+
+	// JSONErrorCodeFailedToBanUsers is the "Failed to ban users" error.
+	JSONErrorCodeFailedToBanUsers JSONErrorCode = 500000
+
+	// JSONErrorCodePollVotingBlocked is the "Poll voting blocked" error.
+	JSONErrorCodePollVotingBlocked JSONErrorCode = 520000
+
+	// JSONErrorCodePollExpired is the "Poll expired" error.
+	JSONErrorCodePollExpired JSONErrorCode = 520001
+
+	// JSONErrorCodeInvalidPollChannelType is the "Invalid channel type for poll creation" error.
+	JSONErrorCodeInvalidPollChannelType JSONErrorCode = 520002
+
+	// JSONErrorCodeCannotEditPollMessage is the "Cannot edit a poll message" error.
+	JSONErrorCodeCannotEditPollMessage JSONErrorCode = 520003
+
+	// JSONErrorCodeCannotUsePollEmoji is the "Cannot use an emoji included with the poll" error.
+	JSONErrorCodeCannotUsePollEmoji JSONErrorCode = 520004
+
+	// JSONErrorCodeCannotExpireNonPollMessage is the "Cannot expire a non-poll message" error.
+	JSONErrorCodeCannotExpireNonPollMessage JSONErrorCode = 520006
+)
+
+// jsonErrorCodeMessages holds the documented message of every JSON error code.
+var jsonErrorCodeMessages = map[JSONErrorCode]string{
+	JSONErrorCodeGeneralError:                          "General error (such as a malformed request body, amongst other things)",
+	JSONErrorCodeUnknownAccount:                        "Unknown account",
+	JSONErrorCodeUnknownApplication:                    "Unknown application",
+	JSONErrorCodeUnknownChannel:                        "Unknown channel",
+	JSONErrorCodeUnknownGuild:                          "Unknown guild",
+	JSONErrorCodeUnknownIntegration:                    "Unknown integration",
+	JSONErrorCodeUnknownInvite:                         "Unknown invite",
+	JSONErrorCodeUnknownMember:                         "Unknown member",
+	JSONErrorCodeUnknownMessage:                        "Unknown message",
+	JSONErrorCodeUnknownPermissionOverwrite:            "Unknown permission overwrite",
+	JSONErrorCodeUnknownProvider:                       "Unknown provider",
+	JSONErrorCodeUnknownRole:                           "Unknown role",
+	JSONErrorCodeUnknownToken:                          "Unknown token",
+	JSONErrorCodeUnknownUser:                           "Unknown user",
+	JSONErrorCodeUnknownEmoji:                          "Unknown emoji",
+	JSONErrorCodeUnknownWebhook:                        "Unknown webhook",
+	JSONErrorCodeUnknownWebhookService:                 "Unknown webhook service",
+	JSONErrorCodeUnknownSession:                        "Unknown session",
+	JSONErrorCodeUnknownAsset:                          "Unknown asset",
+	JSONErrorCodeUnknownBan:                            "Unknown ban",
+	JSONErrorCodeUnknownSKU:                            "Unknown SKU",
+	JSONErrorCodeUnknownStoreListing:                   "Unknown store listing",
+	JSONErrorCodeUnknownEntitlement:                    "Unknown entitlement",
+	JSONErrorCodeUnknownBuild:                          "Unknown build",
+	JSONErrorCodeUnknownLobby:                          "Unknown lobby",
+	JSONErrorCodeUnknownBranch:                         "Unknown branch",
+	JSONErrorCodeUnknownStoreDirectoryLayout:           "Unknown store directory layout",
+	JSONErrorCodeUnknownRedistributable:                "Unknown redistributable",
+	JSONErrorCodeUnknownGiftCode:                       "Unknown gift code",
+	JSONErrorCodeUnknownStream:                         "Unknown stream",
+	JSONErrorCodeUnknownPremiumServerSubscribeCooldown: "Unknown premium server subscribe cooldown",
+	JSONErrorCodeUnknownGuildTemplate:                  "Unknown guild template",
+	JSONErrorCodeUnknownDiscoverableServerCategory:     "Unknown discoverable server category",
+	JSONErrorCodeUnknownSticker:                        "Unknown sticker",
+	JSONErrorCodeUnknownStickerPack:                    "Unknown sticker pack",
+	JSONErrorCodeUnknownInteraction:                    "Unknown interaction",
+	JSONErrorCodeUnknownApplicationCommand:             "Unknown application command",
+	JSONErrorCodeUnknownVoiceState:                     "Unknown voice state",
+	JSONErrorCodeUnknownApplicationCommandPermissions:  "Unknown application command permissions",
+	JSONErrorCodeUnknownStageInstance:                  "Unknown Stage Instance",
+	JSONErrorCodeUnknownGuildMemberVerificationForm:    "Unknown Guild Member Verification Form",
+	JSONErrorCodeUnknownGuildWelcomeScreen:             "Unknown Guild Welcome Screen",
+	JSONErrorCodeUnknownGuildScheduledEvent:            "Unknown Guild Scheduled Event",
+	JSONErrorCodeUnknownGuildScheduledEventUser:        "Unknown Guild Scheduled Event User",
+	JSONErrorCodeUnknownTag:                            "Unknown Tag",
+	JSONErrorCodeUnknownSound:                          "Unknown sound",
+	JSONErrorCodeBotsCannotUseThisEndpoint:             "Bots cannot use this endpoint",
+	JSONErrorCodeOnlyBotsCanUseThisEndpoint:            "Only bots can use this endpoint",
+	JSONErrorCodeExplicitContentCannotBeSent:           "Explicit content cannot be sent to the desired recipient(s)",
+	JSONErrorCodeNotAuthorizedOnApplication:            "You are not authorized to perform this action on this application",
+	JSONErrorCodeSlowmodeRateLimit:                     "This action cannot be performed due to slowmode rate limit",
+	JSONErrorCodeOnlyAccountOwner:                      "Only the owner of this account can perform this action",
+	JSONErrorCodeAnnouncementRateLimit:                 "This message cannot be edited due to announcement rate limits",
+	JSONErrorCodeUnderMinimumAge:                       "Under minimum age",
+	JSONErrorCodeChannelWriteRateLimit:                 "The channel you are writing has hit the write rate limit",
+	JSONErrorCodeServerWriteRateLimit:                  "The write action you are performing on the server has hit the write rate limit",
+	JSONErrorCodeWordsNotAllowed:                       "Your Stage topic, server name, server description, or channel names contain words that are not allowed",
+	JSONErrorCodeGuildPremiumLevelTooLow:               "Guild premium subscription level too low",
+	JSONErrorCodeMaxGuilds:                             "Maximum number of guilds reached (100)",
+	JSONErrorCodeMaxFriends:                            "Maximum number of friends reached (1000)",
+	JSONErrorCodeMaxPins:                               "Maximum number of pins reached for the channel (50)",
+	JSONErrorCodeMaxRecipients:                         "Maximum number of recipients reached (10)",
+	JSONErrorCodeMaxGuildRoles:                         "Maximum number of guild roles reached (250)",
+	JSONErrorCodeMaxWebhooks:                           "Maximum number of webhooks reached (15)",
+	JSONErrorCodeMaxEmojis:                             "Maximum number of emojis reached",
+	JSONErrorCodeMaxReactions:                          "Maximum number of reactions reached (20)",
+	JSONErrorCodeMaxGroupDMs:                           "Maximum number of group DMs reached (10)",
+	JSONErrorCodeMaxGuildChannels:                      "Maximum number of guild channels reached (500)",
+	JSONErrorCodeMaxAttachments:                        "Maximum number of attachments in a message reached (10)",
+	JSONErrorCodeMaxInvites:                            "Maximum number of invites reached (1000)",
+	JSONErrorCodeMaxAnimatedEmojis:                     "Maximum number of animated emojis reached",
+	JSONErrorCodeMaxServerMembers:                      "Maximum number of server members reached",
+	JSONErrorCodeMaxServerCategories:                   "Maximum number of server categories has been reached (5)",
+	JSONErrorCodeGuildAlreadyHasTemplate:               "Guild already has a template",
+	JSONErrorCodeMaxApplicationCommands:                "Maximum number of application commands reached",
+	JSONErrorCodeMaxThreadParticipants:                 "Maximum number of thread participants has been reached (1000)",
+	JSONErrorCodeMaxDailyApplicationCommandCreates:     "Maximum number of daily application command creates has been reached (200)",
+	JSONErrorCodeMaxNonMemberBans:                      "Maximum number of bans for non-guild members have been exceeded",
+	JSONErrorCodeMaxBanFetches:                         "Maximum number of bans fetches has been reached",
+	JSONErrorCodeMaxUncompletedScheduledEvents:         "Maximum number of uncompleted guild scheduled events reached (100)",
+	JSONErrorCodeMaxStickers:                           "Maximum number of stickers reached",
+	JSONErrorCodeMaxPruneRequests:                      "Maximum number of prune requests has been reached. Try again later",
+	JSONErrorCodeMaxGuildWidgetUpdates:                 "Maximum number of guild widget settings updates has been reached. Try again later",
+	JSONErrorCodeMaxSoundboardSounds:                   "Maximum number of soundboard sounds reached",
+	JSONErrorCodeMaxOldMessageEdits:                    "Maximum number of edits to messages older than 1 hour reached. Try again later",
+	JSONErrorCodeMaxPinnedForumThreads:                 "Maximum number of pinned threads in a forum channel has been reached",
+	JSONErrorCodeMaxForumTags:                          "Maximum number of tags in a forum channel has been reached",
+	JSONErrorCodeBitrateTooHigh:                        "Bitrate is too high for channel of this type",
+	JSONErrorCodeMaxPremiumEmojis:                      "Maximum number of premium emojis reached (25)",
+	JSONErrorCodeMaxGuildWebhooks:                      "Maximum number of webhooks per guild reached (1000)",
+	JSONErrorCodeMaxChannelPermissionOverwrites:        "Maximum number of channel permission overwrites reached (1000)",
+	JSONErrorCodeGuildChannelsTooLarge:                 "The channels for this guild are too large",
+	JSONErrorCodeUnauthorized:                          "Unauthorized. Provide a valid token and try again",
+	JSONErrorCodeAccountVerificationRequired:           "You need to verify your account in order to perform this action",
+	JSONErrorCodeOpeningDMsTooFast:                     "You are opening direct messages too fast",
+	JSONErrorCodeSendMessagesDisabled:                  "Send messages has been temporarily disabled",
+	JSONErrorCodeRequestEntityTooLarge:                 "Request entity too large. Try sending something smaller in size",
+	JSONErrorCodeFeatureDisabled:                       "This feature has been temporarily disabled server-side",
+	JSONErrorCodeUserBannedFromGuild:                   "The user is banned from this guild",
+	JSONErrorCodeConnectionRevoked:                     "Connection has been revoked",
+	JSONErrorCodeOnlyConsumableSKUs:                    "Only consumable SKUs can be consumed",
+	JSONErrorCodeOnlySandboxEntitlements:               "You can only delete sandbox entitlements",
+	JSONErrorCodeTargetUserNotInVoice:                  "Target user is not connected to voice",
+	JSONErrorCodeMessageAlreadyCrossposted:             "This message has already been crossposted",
+	JSONErrorCodeApplicationCommandNameExists:          "An application command with that name already exists",
+	JSONErrorCodeInteractionFailedToSend:               "Application interaction failed to send",
+	JSONErrorCodeCannotSendInForumChannel:              "Cannot send a message in a forum channel",
+	JSONErrorCodeInteractionAlreadyAcknowledged:        "Interaction has already been acknowledged",
+	JSONErrorCodeTagNamesMustBeUnique:                  "Tag names must be unique",
+	JSONErrorCodeServiceResourceRateLimited:            "Service resource is being rate limited",
+	JSONErrorCodeNoTagsForNonModerators:                "There are no tags available that can be set by non-moderators",
+	JSONErrorCodeTagRequiredForForumPost:               "A tag is required to create a forum post in this channel",
+	JSONErrorCodeEntitlementAlreadyGranted:             "An entitlement has already been granted for this resource",
+	JSONErrorCodeMaxFollowupMessages:                   "This interaction has hit the maximum number of follow up messages",
+	JSONErrorCodeCloudflareBlocked:                     "Cloudflare is blocking your request. This can often be resolved by setting a proper User Agent",
+	JSONErrorCodeMissingAccess:                         "Missing access",
+	JSONErrorCodeInvalidAccountType:                    "Invalid account type",
+	JSONErrorCodeCannotExecuteOnDMChannel:              "Cannot execute action on a DM channel",
+	JSONErrorCodeGuildWidgetDisabled:                   "Guild widget disabled",
+	JSONErrorCodeCannotEditOtherUserMessage:            "Cannot edit a message authored by another user",
+	JSONErrorCodeCannotSendEmptyMessage:                "Cannot send an empty message",
+	JSONErrorCodeCannotSendMessagesToUser:              "Cannot send messages to this user",
+	JSONErrorCodeCannotSendMessagesInNonTextChannel:    "Cannot send messages in a non-text channel",
+	JSONErrorCodeChannelVerificationLevelTooHigh:       "Channel verification level is too high for you to gain access",
+	JSONErrorCodeOAuth2ApplicationHasNoBot:             "OAuth2 application does not have a bot",
+	JSONErrorCodeOAuth2ApplicationLimitReached:         "OAuth2 application limit reached",
+	JSONErrorCodeInvalidOAuth2State:                    "Invalid OAuth2 state",
+	JSONErrorCodeMissingPermissions:                    "You lack permissions to perform that action",
+	JSONErrorCodeInvalidAuthenticationToken:            "Invalid authentication token provided",
+	JSONErrorCodeNoteTooLong:                           "Note was too long",
+	JSONErrorCodeInvalidBulkDeleteCount:                "Provided too few or too many messages to delete. Must provide at least 2 and fewer than 100 messages to delete",
+	JSONErrorCodeInvalidMFALevel:                       "Invalid MFA Level",
+	JSONErrorCodeCannotPinInOtherChannel:               "A message can only be pinned to the channel it was sent in",
+	JSONErrorCodeInvalidInviteCode:                     "Invite code was either invalid or taken",
+	JSONErrorCodeCannotExecuteOnSystemMessage:          "Cannot execute action on a system message",
+	JSONErrorCodeCannotExecuteOnChannelType:            "Cannot execute action on this channel type",
+	JSONErrorCodeInvalidOAuth2AccessToken:              "Invalid OAuth2 access token provided",
+	JSONErrorCodeMissingOAuth2Scope:                    "Missing required OAuth2 scope",
+	JSONErrorCodeInvalidWebhookToken:                   "Invalid webhook token provided",
+	JSONErrorCodeInvalidRole:                           "Invalid role",
+	JSONErrorCodeInvalidRecipients:                     "Invalid Recipient(s)",
+	JSONErrorCodeMessageTooOldToBulkDelete:             "A message provided was too old to bulk delete",
+	JSONErrorCodeInvalidFormBody:                       "Invalid form body (returned for both application/json and multipart/form-data bodies), or invalid Content-Type provided",
+	JSONErrorCodeInviteAcceptedToGuildWithoutBot:       "An invite was accepted to a guild the application's bot is not in",
+	JSONErrorCodeInvalidActivityAction:                 "Invalid Activity Action",
+	JSONErrorCodeInvalidAPIVersion:                     "Invalid API version provided",
+	JSONErrorCodeFileTooLarge:                          "File uploaded exceeds the maximum size",
+	JSONErrorCodeInvalidFileUploaded:                   "Invalid file uploaded",
+	JSONErrorCodeCannotSelfRedeemGift:                  "Cannot self-redeem this gift",
+	JSONErrorCodeInvalidGuild:                          "Invalid Guild",
+	JSONErrorCodeInvalidSKU:                            "Invalid SKU",
+	JSONErrorCodeInvalidRequestOrigin:                  "Invalid request origin",
+	JSONErrorCodeInvalidMessageType:                    "Invalid message type",
+	JSONErrorCodePaymentSourceRequired:                 "Payment source required to redeem gift",
+	JSONErrorCodeCannotModifySystemWebhook:             "Cannot modify a system webhook",
+	JSONErrorCodeCannotDeleteCommunityChannel:          "Cannot delete a channel required for Community guilds",
+	JSONErrorCodeCannotEditMessageStickers:             "Cannot edit stickers within a message",
+	JSONErrorCodeInvalidSticker:                        "Invalid sticker sent",
+	JSONErrorCodeThreadArchived:                        "Tried to perform an operation on an archived thread, such as editing a message or adding a user to the thread",
+	JSONErrorCodeInvalidThreadNotificationSettings:     "Invalid thread notification settings",
+	JSONErrorCodeBeforeEarlierThanThreadCreation:       "before value is earlier than the thread creation date",
+	JSONErrorCodeCommunityChannelsMustBeText:           "Community server channels must be text channels",
+	JSONErrorCodeEventEntityTypeMismatch:               "The entity type of the event is different from the entity you are trying to start the event for",
+	JSONErrorCodeServerNotAvailableInLocation:          "This server is not available in your location",
+	JSONErrorCodeMonetizationRequired:                  "This server needs monetization enabled in order to perform this action",
+	JSONErrorCodeMoreBoostsRequired:                    "This server needs more boosts to perform this action",
+	JSONErrorCodeInvalidJSONBody:                       "The request body contains invalid JSON",
+	JSONErrorCodeInvalidFile:                           "The provided file is invalid",
+	JSONErrorCodeInvalidFileType:                       "The provided file type is invalid",
+	JSONErrorCodeFileDurationTooLong:                   "The provided file duration exceeds maximum of 5.2 seconds",
+	JSONErrorCodeOwnerCannotBePendingMember:            "Owner cannot be pending member",
+	JSONErrorCodeCannotTransferOwnershipToBot:          "Ownership cannot be transferred to a bot user",
+	JSONErrorCodeCannotResizeAsset:                     "Failed to resize asset below the maximum size: 262144",
+	JSONErrorCodeCannotMixSubscriptionRoles:            "Cannot mix subscription and non subscription roles for an emoji",
+	JSONErrorCodeCannotConvertPremiumEmoji:             "Cannot convert between premium emoji and normal emoji",
+	JSONErrorCodeUploadedFileNotFound:                  "Uploaded file not found",
+	JSONErrorCodeInvalidEmoji:                          "The specified emoji is invalid",
+	JSONErrorCodeVoiceMessageAdditionalContent:         "Voice messages do not support additional content",
+	JSONErrorCodeVoiceMessageSingleAttachment:          "Voice messages must have a single audio attachment",
+	JSONErrorCodeVoiceMessageMetadataRequired:          "Voice messages must have supporting metadata",
+	JSONErrorCodeVoiceMessageCannotBeEdited:            "Voice messages cannot be edited",
+	JSONErrorCodeCannotDeleteSubscriptionIntegration:   "Cannot delete guild subscription integration",
+	JSONErrorCodeCannotSendVoiceMessages:               "You cannot send voice messages in this channel",
+	JSONErrorCodeAccountMustBeVerified:                 "The user account must first be verified",
+	JSONErrorCodeInvalidFileDuration:                   "The provided file does not have a valid duration",
+	JSONErrorCodeCannotSendSticker:                     "You do not have permission to send this sticker",
+	JSONErrorCodeTwoFactorRequired:                     "Two factor is required for this operation",
+	JSONErrorCodeNoUsersWithDiscordTag:                 "No users with DiscordTag exist",
+	JSONErrorCodeReactionBlocked:                       "Reaction was blocked",
+	JSONErrorCodeCannotUseBurstReactions:               "User cannot use burst reactions",
+	JSONErrorCodeApplicationNotAvailable:               "Application not yet available. Try again later",
+	JSONErrorCodeAPIResourceOverloaded:                 "API resource is currently overloaded. Try again a little later",
+	JSONErrorCodeStageAlreadyOpen:                      "The Stage is already open",
+	JSONErrorCodeCannotReplyWithoutReadHistory:         "Cannot reply without permission to read message history",
+	JSONErrorCodeThreadAlreadyCreated:                  "A thread has already been created for this message",
+	JSONErrorCodeThreadLocked:                          "Thread is locked",
+	JSONErrorCodeMaxActiveThreads:                      "Maximum number of active threads reached",
+	JSONErrorCodeMaxActiveAnnouncementThreads:          "Maximum number of active announcement threads reached",
+	JSONErrorCodeInvalidLottieJSON:                     "Invalid JSON for uploaded Lottie file",
+	JSONErrorCodeLottieRasterizedImages:                "Uploaded Lotties cannot contain rasterized images such as PNG or JPEG",
+	JSONErrorCodeStickerMaxFramerateExceeded:           "Sticker maximum framerate exceeded",
+	JSONErrorCodeStickerMaxFramesExceeded:              "Sticker frame count exceeds maximum of 1000 frames",
+	JSONErrorCodeLottieMaxDimensionsExceeded:           "Lottie animation maximum dimensions exceeded",
+	JSONErrorCodeStickerInvalidFramerate:               "Sticker frame rate is either too small or too large",
+	JSONErrorCodeStickerMaxDurationExceeded:            "Sticker animation duration exceeds maximum of 5 seconds",
+	JSONErrorCodeCannotUpdateFinishedEvent:             "Cannot update a finished event",
+	JSONErrorCodeFailedToCreateStageForEvent:           "Failed to create stage needed for stage event",
+	JSONErrorCodeMessageBlockedByAutoMod:               "Message was blocked by automatic moderation",
+	JSONErrorCodeTitleBlockedByAutoMod:                 "Title was blocked by automatic moderation",
+	JSONErrorCodeForumWebhookThreadRequired:            "Webhooks posted to forum channels must have a thread_name or thread_id",
+	JSONErrorCodeForumWebhookThreadConflict:            "Webhooks posted to forum channels cannot have both a thread_name and thread_id",
+	JSONErrorCodeWebhookThreadsOnlyInForums:            "Webhooks can only create threads in forum channels",
+	JSONErrorCodeWebhookServicesInForums:               "Webhook services cannot be used in forum channels",
+	JSONErrorCodeMessageBlockedByLinkFilter:            "Message blocked by harmful links filter",
+	JSONErrorCodeCannotEnableOnboarding:                "Cannot enable onboarding, requirements are not met",
+	JSONErrorCodeCannotUpdateOnboarding:                "Cannot update onboarding while below requirements",
+	JSONErrorCodeFailedToBanUsers:                      "Failed to ban users",
+	JSONErrorCodePollVotingBlocked:                     "Poll voting blocked",
+	JSONErrorCodePollExpired:                           "Poll expired",
+	JSONErrorCodeInvalidPollChannelType:                "Invalid channel type for poll creation",
+	JSONErrorCodeCannotEditPollMessage:                 "Cannot edit a poll message",
+	JSONErrorCodeCannotUsePollEmoji:                    "Cannot use an emoji included with the poll",
+	JSONErrorCodeCannotExpireNonPollMessage:            "Cannot expire a non-poll message",
+}
+
+// String returns the documented message of the code, or "Unknown JSON error code N".
+func (c JSONErrorCode) String() string {
+	if msg, ok := jsonErrorCodeMessages[c]; ok {
+		return msg
+	}
+	return "Unknown JSON error code " + strconv.Itoa(int(c))
+}
+
+// Error implements the error interface, so codes can be used as errors.Is targets.
+func (c JSONErrorCode) Error() string {
+	return "goda: discord json error code " + strconv.Itoa(int(c)) + ": " + c.String()
+}
@@ -13,7 +13,13 @@
 
 package goda
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+)
 
 // Common errors returned by the goda library.
 var (
@@ -69,12 +75,95 @@ var (
 
 	// ErrDMNotAllowed is returned when a DM cannot be sent to a user.
 	ErrDMNotAllowed = errors.New("goda: cannot send DM to this user")
+
+	// ErrShardNotConnected is returned when a payload is sent on a shard
+	// that has no Gateway connection.
+	ErrShardNotConnected = errors.New("goda: shard is not connected")
+
+	// ErrShardNotFound is returned when no shard of the client handles a guild.
+	ErrShardNotFound = errors.New("goda: no shard handles this guild")
+
+	// ErrSessionStartLimit is returned when fewer session starts remain
+	// than the shards to start.
+	ErrSessionStartLimit = errors.New("goda: session start limit too low to start the shards")
+
+	// ErrClusterReshard is returned when resharding a client running a subset of the shards.
+	ErrClusterReshard = errors.New("goda: cannot reshard in cluster mode")
+
+	// ErrHeartbeatTimeout is reported when a shard disconnects
+	// because the Gateway did not acknowledge its last heartbeat.
+	ErrHeartbeatTimeout = errors.New("goda: gateway did not acknowledge the heartbeat")
+
+	// ErrReconnectRequested is reported when a shard disconnects
+	// because the Gateway asked it to reconnect.
+	ErrReconnectRequested = errors.New("goda: gateway requested a reconnect")
+
+	// ErrVoiceConnectionClosed is returned when audio is sent on a closed voice connection.
+	ErrVoiceConnectionClosed = errors.New("goda: voice connection is closed")
+
+	// ErrInvalidMembersRequest is returned when a guild members request
+	// sets both a query and user IDs, or more than 100 user IDs.
+	ErrInvalidMembersRequest = errors.New("goda: invalid guild members request")
+
+	// ErrInvalidMembersChunk is returned when a guild members request receives a chunk
+	// with an index out of range or already received.
+	ErrInvalidMembersChunk = errors.New("goda: invalid guild members chunk")
+
+	// ErrMembersRequestInterrupted is returned when the shard of a guild members request
+	// disconnects before every chunk was received.
+	ErrMembersRequestInterrupted = errors.New("goda: shard disconnected before every guild members chunk was received")
+
+	// ErrMaxRetries is returned when a REST request still fails
+	// after being retried on rate limits, server or network errors.
+	ErrMaxRetries = errors.New("goda: max retries reached")
 )
 
-// DiscordAPIError represents an error returned by the Discord API.
+// GatewayCloseError is returned when the Gateway closes the connection of a shard
+// with a close code that does not allow reconnecting.
+type GatewayCloseError struct {
+	// ShardID is the ID of the closed shard.
+	ShardID int
+
+	// Code is the Gateway close event code.
+	Code GatewayCloseEventCode
+
+	// Reason is the close reason sent by Discord.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *GatewayCloseError) Error() string {
+	return "goda: shard " + strconv.Itoa(e.ShardID) + " closed by gateway with code " +
+		strconv.Itoa(int(e.Code)) + ": " + e.Reason
+}
+
+// DiscordAPIError is returned by REST API calls when Discord answers with a non-2xx status.
+//
+// It matches the sentinel errors of its status and code through errors.Is:
+//   - ErrUnauthorized and ErrInvalidToken: 401 Unauthorized.
+//   - ErrMissingPermissions: JSONErrorCodeMissingPermissions, or 403 Forbidden without a "code" field.
+//   - ErrNotFound: 404 Not Found.
+//   - ErrRateLimited: 429 Too Many Requests.
+//   - ErrDMNotAllowed: JSONErrorCodeCannotSendMessagesToUser.
+//   - The JSONErrorCode of its Code, JSONErrorCodeGeneralError only if the response had a "code" of 0.
+//
+// Usage example:
+//
+//	_, err := client.FetchChannel(channelID)
+//	var apiErr *goda.DiscordAPIError
+//	if errors.As(err, &apiErr) {
+//	    fmt.Println(apiErr.HTTPStatus, apiErr.Code, apiErr.FieldErrors)
+//	}
+//	if errors.Is(err, goda.JSONErrorCodeUnknownChannel) {
+//	    // the channel was deleted
+//	}
+//
+// Reference: https://discord.com/developers/docs/reference#error-messages
 type DiscordAPIError struct {
-	// Code is the Discord error code.
-	Code int `json:"code"`
+	// Code is the Discord JSON error code, 0 if the response had none.
+	//
+	// Only a response with a "code" of 0 matches JSONErrorCodeGeneralError.
+	Code JSONErrorCode `json:"code"`
 
 	// Message is the error message from Discord.
 	Message string `json:"message"`
@@ -84,11 +173,109 @@ type DiscordAPIError struct {
 
 	// Errors contains nested validation errors.
 	Errors map[string]interface{} `json:"errors,omitempty"`
+
+	// FieldErrors are the validation errors of Errors, flattened by field path.
+	FieldErrors []DiscordFieldError `json:"-"`
+
+	hasCode bool // whether the response had a "code" field, to tell code 0 apart from none
+}
+
+// DiscordFieldError is a validation error of a field of a request.
+type DiscordFieldError struct {
+	// Path is the path of the field, e.g. "embeds.0.title".
+	Path string
+
+	// Code is the validation error code, e.g. "BASE_TYPE_REQUIRED".
+	Code string
+
+	// Message is the validation error message.
+	Message string
+}
+
+// newDiscordAPIError decodes the body of a non-2xx response with the given status.
+//
+// Bodies that are not a Discord error object use the status text as message.
+func newDiscordAPIError(status int, body []byte) *DiscordAPIError {
+	e := &DiscordAPIError{HTTPStatus: status}
+	if err := json.Unmarshal(body, e); err != nil || e.Message == "" {
+		e.Message = http.StatusText(status)
+	}
+	e.HTTPStatus = status
+	e.FieldErrors = flattenFieldErrors("", e.Errors, e.FieldErrors)
+
+	var code struct {
+		Code *JSONErrorCode `json:"code"`
+	}
+	e.hasCode = json.Unmarshal(body, &code) == nil && code.Code != nil
+	return e
+}
+
+// flattenFieldErrors appends the "_errors" lists found in the nested errors object to dst,
+// with the path of the object holding them.
+func flattenFieldErrors(path string, errs map[string]interface{}, dst []DiscordFieldError) []DiscordFieldError {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := errs[key].(type) {
+		case []interface{}:
+			if key != "_errors" {
+				continue
+			}
+			for _, item := range value {
+				fields, _ := item.(map[string]interface{})
+				code, _ := fields["code"].(string)
+				message, _ := fields["message"].(string)
+				dst = append(dst, DiscordFieldError{Path: path, Code: code, Message: message})
+			}
+		case map[string]interface{}:
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			dst = flattenFieldErrors(child, value, dst)
+		}
+	}
+	return dst
 }
 
 // Error implements the error interface.
 func (e *DiscordAPIError) Error() string {
-	return e.Message
+	msg := "goda: discord api error " + strconv.Itoa(e.HTTPStatus)
+	if e.Code != 0 {
+		msg += " (code " + strconv.Itoa(int(e.Code)) + ")"
+	}
+	msg += ": " + e.Message
+	for _, field := range e.FieldErrors {
+		msg += "; " + field.Path + ": " + field.Message
+	}
+	return msg
+}
+
+// Is reports whether target is a sentinel error or the JSONErrorCode matching the status or code of e.
+func (e *DiscordAPIError) Is(target error) bool {
+	if code, ok := target.(JSONErrorCode); ok {
+		// a response without a code is not a general error
+		return e.Code == code && (code != JSONErrorCodeGeneralError || e.hasCode)
+	}
+
+	switch target {
+	case ErrUnauthorized, ErrInvalidToken:
+		return e.HTTPStatus == http.StatusUnauthorized
+	case ErrMissingPermissions:
+		// other 403 codes, such as 50007 or 50001, are not missing permissions
+		return e.Code == JSONErrorCodeMissingPermissions || (!e.hasCode && e.Code == 0 && e.HTTPStatus == http.StatusForbidden)
+	case ErrNotFound:
+		return e.HTTPStatus == http.StatusNotFound
+	case ErrRateLimited:
+		return e.HTTPStatus == http.StatusTooManyRequests
+	case ErrDMNotAllowed:
+		return e.Code == JSONErrorCodeCannotSendMessagesToUser
+	}
+	return false
 }
 
 // IsNotFound returns true if this is a 404 Not Found error.
@@ -0,0 +1,150 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNewDiscordAPIError_FlattensFieldErrors(t *testing.T) {
+	body := `{"code":50035,"message":"Invalid Form Body","errors":{` +
+		`"content":{"_errors":[{"code":"BASE_TYPE_MAX_LENGTH","message":"Must be 2000 or fewer in length."}]},` +
+		`"embeds":{"0":{"title":{"_errors":[{"code":"BASE_TYPE_REQUIRED","message":"This field is required"}]}}}}}`
+
+	err := newDiscordAPIError(http.StatusBadRequest, []byte(body))
+	if err.HTTPStatus != 400 || err.Code != 50035 || err.Message != "Invalid Form Body" {
+		t.Fatalf("unexpected error fields: %+v", err)
+	}
+
+	want := []DiscordFieldError{
+		{Path: "content", Code: "BASE_TYPE_MAX_LENGTH", Message: "Must be 2000 or fewer in length."},
+		{Path: "embeds.0.title", Code: "BASE_TYPE_REQUIRED", Message: "This field is required"},
+	}
+	if !reflect.DeepEqual(err.FieldErrors, want) {
+		t.Fatalf("expected field errors %+v, got %+v", want, err.FieldErrors)
+	}
+
+	wantMsg := "goda: discord api error 400 (code 50035): Invalid Form Body" +
+		"; content: Must be 2000 or fewer in length.; embeds.0.title: This field is required"
+	if err.Error() != wantMsg {
+		t.Fatalf("expected message %q, got %q", wantMsg, err.Error())
+	}
+}
+
+func TestNewDiscordAPIError_NonJSONBody(t *testing.T) {
+	err := newDiscordAPIError(http.StatusBadGateway, []byte("<html>bad gateway</html>"))
+	if err.Message != "Bad Gateway" || err.Code != 0 || err.HTTPStatus != 502 {
+		t.Fatalf("unexpected error fields: %+v", err)
+	}
+}
+
+func TestDiscordAPIError_IsSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		target error
+	}{
+		{401, `{"code":0,"message":"401: Unauthorized"}`, ErrUnauthorized},
+		{401, `{"code":0,"message":"401: Unauthorized"}`, ErrInvalidToken},
+		{403, `{"code":50013,"message":"Missing Permissions"}`, ErrMissingPermissions},
+		{403, `{"code":50007,"message":"Cannot send messages to this user"}`, ErrDMNotAllowed},
+		{404, `{"code":10003,"message":"Unknown Channel"}`, ErrNotFound},
+		{429, `{"message":"You are being rate limited.","retry_after":1}`, ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		err := error(newDiscordAPIError(tt.status, []byte(tt.body)))
+		if !errors.Is(err, tt.target) {
+			t.Errorf("expected %d %s to match %v", tt.status, tt.body, tt.target)
+		}
+		if errors.Is(err, ErrChannelNotText) {
+			t.Errorf("expected %d %s not to match %v", tt.status, tt.body, ErrChannelNotText)
+		}
+	}
+}
+
+func TestDiscordAPIError_MissingPermissionsByCode(t *testing.T) {
+	for _, body := range []string{
+		`{"code":50007,"message":"Cannot send messages to this user"}`,
+		`{"code":50001,"message":"Missing Access"}`,
+	} {
+		if err := newDiscordAPIError(http.StatusForbidden, []byte(body)); errors.Is(err, ErrMissingPermissions) {
+			t.Errorf("expected 403 %s not to match ErrMissingPermissions", body)
+		}
+	}
+	if err := newDiscordAPIError(http.StatusForbidden, []byte("<html>forbidden</html>")); !errors.Is(err, ErrMissingPermissions) {
+		t.Error("expected a 403 without a code to match ErrMissingPermissions")
+	}
+}
+
+func TestDiscordAPIError_GeneralErrorOnlyWithCode(t *testing.T) {
+	if err := newDiscordAPIError(http.StatusBadRequest, []byte(`{"code":0,"message":"400: Bad Request"}`)); !errors.Is(err, JSONErrorCodeGeneralError) {
+		t.Error("expected a response with code 0 to match JSONErrorCodeGeneralError")
+	}
+	for _, body := range []string{`{"message":"500: Internal Server Error"}`, "<html>bad gateway</html>", ""} {
+		if err := newDiscordAPIError(http.StatusBadGateway, []byte(body)); errors.Is(err, JSONErrorCodeGeneralError) {
+			t.Errorf("expected %q without a code not to match JSONErrorCodeGeneralError", body)
+		}
+	}
+	if err := newDiscordAPIError(http.StatusForbidden, []byte(`{"code":0,"message":"403: Forbidden"}`)); errors.Is(err, ErrMissingPermissions) {
+		t.Error("expected a 403 with code 0 not to match ErrMissingPermissions")
+	}
+}
+
+func TestRestApi_ReturnsDiscordAPIError(t *testing.T) {
+	req := newTestRequester(func(*http.Request) (*http.Response, error) {
+		return newMockResponse(404, `{"code":10003,"message":"Unknown Channel"}`, nil), nil
+	})
+	api := newRestApi(req, NewDefaultLogger(io.Discard, LogLevelFatalLevel))
+
+	_, err := api.FetchChannel(123)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	var apiErr *DiscordAPIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != 404 || apiErr.Code != 10003 {
+		t.Fatalf("expected a 404 DiscordAPIError with code 10003, got %v", err)
+	}
+}
+
+func TestDiscordAPIError_IsJSONErrorCode(t *testing.T) {
+	err := error(newDiscordAPIError(http.StatusNotFound, []byte(`{"code":10008,"message":"Unknown Message"}`)))
+
+	var apiErr *DiscordAPIError
+	if !errors.As(err, &apiErr) || apiErr.Code != JSONErrorCodeUnknownMessage {
+		t.Fatalf("expected code %d, got %v", JSONErrorCodeUnknownMessage, err)
+	}
+	if !errors.Is(err, JSONErrorCodeUnknownMessage) {
+		t.Fatalf("expected %v to match JSONErrorCodeUnknownMessage", err)
+	}
+	if errors.Is(err, JSONErrorCodeUnknownChannel) {
+		t.Fatalf("expected %v not to match JSONErrorCodeUnknownChannel", err)
+	}
+}
+
+func TestJSONErrorCode_String(t *testing.T) {
+	if got := JSONErrorCodeMissingPermissions.String(); got != "You lack permissions to perform that action" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if got := JSONErrorCode(1).String(); got != "Unknown JSON error code 1" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if got := JSONErrorCodeUnknownMessage.Error(); got != "goda: discord json error code 10008: Unknown message" {
+		t.Fatalf("unexpected error %q", got)
+	}
+}
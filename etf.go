@@ -0,0 +1,1625 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+/*****************************
+ *  Erlang External Term Format
+ *****************************/
+
+// ETF (Erlang External Term Format) support for the Gateway.
+//
+// Received terms are decoded directly into the typed events, following the
+// decoding rules of encoding/json: struct fields are matched by their json
+// tag, and types with custom JSON decoding implement etfUnmarshaler. Fields
+// holding polymorphic values (channels, components, interactions...) are
+// decoded through etfInterfaceDecoder. Outgoing payloads are built as JSON and
+// transcoded into ETF right before being written.
+//
+// Mapping of received terms:
+//   - maps decode into structs and maps, atom and binary keys are field names.
+//   - lists, tuples and byte lists decode into slices and arrays.
+//   - binaries and atoms decode into strings, the atoms nil, true and false
+//     into null, true and false.
+//   - integers and big integers (e.g. snowflakes) decode into numbers.
+//
+// Reference: https://www.erlang.org/doc/apps/erts/erl_ext_dist.html
+
+const (
+	etfVersion       = 131
+	etfNewFloat      = 70
+	etfCompressed    = 80
+	etfSmallInteger  = 97
+	etfInteger       = 98
+	etfFloat         = 99
+	etfAtom          = 100
+	etfSmallTuple    = 104
+	etfLargeTuple    = 105
+	etfNil           = 106
+	etfString        = 107
+	etfList          = 108
+	etfBinary        = 109
+	etfSmallBig      = 110
+	etfLargeBig      = 111
+	etfSmallAtom     = 115
+	etfMap           = 116
+	etfAtomUTF8      = 118
+	etfSmallAtomUTF8 = 119
+)
+
+// etfMaxDepth limits the nesting of decoded terms to protect the stack
+// against malformed payloads.
+const etfMaxDepth = 256
+
+var (
+	errETFVersion     = errors.New("goda: etf: unsupported version")
+	errETFTruncated   = errors.New("goda: etf: truncated term")
+	errETFTooDeep     = errors.New("goda: etf: term nested too deeply")
+	errETFImproper    = errors.New("goda: etf: improper lists are not supported")
+	errETFTrailing    = errors.New("goda: etf: trailing data after term")
+	errETFOverflow    = errors.New("goda: etf: integer overflows its type")
+	errETFInvalidJSON = errors.New("goda: etf: cannot encode invalid json")
+)
+
+// unmarshalEventData decodes the data of a Gateway payload into v,
+// as ETF if it starts with the ETF version byte and as JSON otherwise.
+func unmarshalEventData(data []byte, v any) error {
+	if len(data) > 0 && data[0] == etfVersion {
+		return unmarshalETF(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+/*****************************
+ *        Decoding
+ *****************************/
+
+// etfUnmarshaler is implemented by types decoding themselves from an ETF term,
+// the ETF counterpart of json.Unmarshaler.
+//
+// unmarshalETF must consume exactly one term. It is not called for the nil atom,
+// which leaves the value unchanged like a JSON null.
+type etfUnmarshaler interface {
+	unmarshalETF(d *etfDecoder) error
+}
+
+// etfInterfaceDecoder returns the decoder of the polymorphic values of the
+// interface type t found in events, which peeks at their type field.
+func etfInterfaceDecoder(t reflect.Type) func(d *etfDecoder) (any, error) {
+	switch t {
+	case reflect.TypeFor[Channel](),
+		reflect.TypeFor[MessageChannel](),
+		reflect.TypeFor[NamedChannel](),
+		reflect.TypeFor[GuildChannel](),
+		reflect.TypeFor[GuildMessageChannel](),
+		reflect.TypeFor[PositionedChannel](),
+		reflect.TypeFor[CategorizedChannel](),
+		reflect.TypeFor[AudioChannel]():
+		return func(d *etfDecoder) (any, error) { return unmarshalChannelETF(d) }
+	case reflect.TypeFor[Component](),
+		reflect.TypeFor[LayoutComponent](),
+		reflect.TypeFor[InteractiveComponent](),
+		reflect.TypeFor[SectionSubComponent](),
+		reflect.TypeFor[SectionAccessoryComponent](),
+		reflect.TypeFor[ContainerSubComponent](),
+		reflect.TypeFor[LabelSubComponent]():
+		return func(d *etfDecoder) (any, error) { return unmarshalComponentETF(d) }
+	case reflect.TypeFor[Interaction]():
+		return func(d *etfDecoder) (any, error) { return unmarshalInteractionETF(d) }
+	case reflect.TypeFor[ApplicationCommand]():
+		return func(d *etfDecoder) (any, error) { return unmarshalApplicationCommandETF(d) }
+	case reflect.TypeFor[ApplicationCommandOption]():
+		return func(d *etfDecoder) (any, error) { return unmarshalApplicationCommandOptionETF(d) }
+	}
+	return nil
+}
+
+// etfDecoder decodes an ETF term into Go values.
+type etfDecoder struct {
+	src   []byte
+	pos   int
+	depth int
+}
+
+// unmarshalETF decodes the ETF encoded data into the value pointed to by v.
+func unmarshalETF(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("goda: etf: unmarshal target must be a non-nil pointer")
+	}
+	d, err := newETFDecoder(data)
+	if err != nil {
+		return err
+	}
+	if err := etfDecoderFor(rv.Type().Elem())(d, rv.Elem()); err != nil {
+		return err
+	}
+	if d.pos != len(d.src) {
+		return errETFTrailing
+	}
+	return nil
+}
+
+// newETFDecoder returns a decoder positioned on the term of data,
+// inflating it first if it is compressed.
+func newETFDecoder(data []byte) (*etfDecoder, error) {
+	if len(data) == 0 || data[0] != etfVersion {
+		return nil, errETFVersion
+	}
+	if len(data) < 2 || data[1] != etfCompressed {
+		return &etfDecoder{src: data, pos: 1}, nil
+	}
+
+	if len(data) < 6 {
+		return nil, errETFTruncated
+	}
+	size := binary.BigEndian.Uint32(data[2:6])
+	zr, err := zlib.NewReader(bytes.NewReader(data[6:]))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	inflated := make([]byte, size)
+	if _, err := io.ReadFull(zr, inflated); err != nil {
+		return nil, err
+	}
+	return &etfDecoder{src: inflated}, nil
+}
+
+// unmarshal decodes the next term into the value pointed to by v.
+func (d *etfDecoder) unmarshal(v any) error {
+	rv := reflect.ValueOf(v).Elem()
+	return etfDecoderFor(rv.Type())(d, rv)
+}
+
+// peek decodes the next term into the value pointed to by v without consuming it.
+func (d *etfDecoder) peek(v any) error {
+	sub := *d
+	return sub.unmarshal(v)
+}
+
+// peekTag returns the tag of the next term without consuming it.
+func (d *etfDecoder) peekTag() (byte, error) {
+	if d.pos >= len(d.src) {
+		return 0, errETFTruncated
+	}
+	return d.src[d.pos], nil
+}
+
+// read consumes the next n bytes of the term.
+func (d *etfDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.src)-d.pos < n {
+		return nil, errETFTruncated
+	}
+	b := d.src[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readLen consumes a big endian length of size bytes (1, 2 or 4).
+func (d *etfDecoder) readLen(size int) (int, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return int(b[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(b)), nil
+	default:
+		return int(binary.BigEndian.Uint32(b)), nil
+	}
+}
+
+// enter increments the nesting depth, failing past etfMaxDepth.
+func (d *etfDecoder) enter() error {
+	d.depth++
+	if d.depth > etfMaxDepth {
+		return errETFTooDeep
+	}
+	return nil
+}
+
+// atomName returns the name of the atom at the current position, ok is false
+// if the next term is not an atom. The atom is not consumed.
+func (d *etfDecoder) atomName() (name []byte, size int, ok bool) {
+	if d.pos >= len(d.src) {
+		return nil, 0, false
+	}
+	var n, head int
+	switch d.src[d.pos] {
+	case etfSmallAtom, etfSmallAtomUTF8:
+		if d.pos+2 > len(d.src) {
+			return nil, 0, false
+		}
+		n, head = int(d.src[d.pos+1]), 2
+	case etfAtom, etfAtomUTF8:
+		if d.pos+3 > len(d.src) {
+			return nil, 0, false
+		}
+		n, head = int(binary.BigEndian.Uint16(d.src[d.pos+1:])), 3
+	default:
+		return nil, 0, false
+	}
+	if d.pos+head+n > len(d.src) {
+		return nil, 0, false
+	}
+	return d.src[d.pos+head : d.pos+head+n], head + n, true
+}
+
+// nilAtom consumes the next term if it is the nil atom.
+func (d *etfDecoder) nilAtom() bool {
+	name, size, ok := d.atomName()
+	if ok && (string(name) == "nil" || string(name) == "null") {
+		d.pos += size
+		return true
+	}
+	return false
+}
+
+// typeError returns the error of decoding the term with the given tag into t.
+func (d *etfDecoder) typeError(tag byte, t reflect.Type) error {
+	return errors.New("goda: etf: cannot decode term " + strconv.Itoa(int(tag)) + " into " + t.String())
+}
+
+// integer consumes an integer term, returning its absolute value and sign.
+func (d *etfDecoder) integer() (v uint64, negative bool, err error) {
+	tag, err := d.read(1)
+	if err != nil {
+		return 0, false, err
+	}
+	switch tag[0] {
+	case etfSmallInteger:
+		b, err := d.read(1)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(b[0]), false, nil
+	case etfInteger:
+		b, err := d.read(4)
+		if err != nil {
+			return 0, false, err
+		}
+		i := int64(int32(binary.BigEndian.Uint32(b)))
+		if i < 0 {
+			return uint64(-i), true, nil
+		}
+		return uint64(i), false, nil
+	case etfSmallBig, etfLargeBig:
+		size := 1
+		if tag[0] == etfLargeBig {
+			size = 4
+		}
+		n, err := d.readLen(size)
+		if err != nil {
+			return 0, false, err
+		}
+		b, err := d.read(n + 1)
+		if err != nil {
+			return 0, false, err
+		}
+		digits := b[1:]
+		for len(digits) > 0 && digits[len(digits)-1] == 0 {
+			digits = digits[:len(digits)-1]
+		}
+		if len(digits) > 8 {
+			return 0, false, errETFOverflow
+		}
+		for i := len(digits) - 1; i >= 0; i-- {
+			v = v<<8 | uint64(digits[i])
+		}
+		return v, b[0] != 0 && v != 0, nil
+	}
+	d.pos--
+	return 0, false, errors.New("goda: etf: expected an integer, got term " + strconv.Itoa(int(tag[0])))
+}
+
+// float consumes a float term.
+func (d *etfDecoder) float() (float64, error) {
+	tag, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	switch tag[0] {
+	case etfNewFloat:
+		b, err := d.read(8)
+		if err != nil {
+			return 0, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case etfFloat:
+		b, err := d.read(31)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(string(bytes.TrimRight(b, "\x00")), 64)
+	}
+	d.pos--
+	return 0, errors.New("goda: etf: expected a float, got term " + strconv.Itoa(int(tag[0])))
+}
+
+// number consumes an integer or float term as a float64.
+func (d *etfDecoder) number() (float64, error) {
+	tag, err := d.peekTag()
+	if err != nil {
+		return 0, err
+	}
+	if tag == etfNewFloat || tag == etfFloat {
+		return d.float()
+	}
+	v, negative, err := d.integer()
+	if negative {
+		return -float64(v), err
+	}
+	return float64(v), err
+}
+
+// text consumes a binary, byte list or atom term, returning its bytes.
+//
+// The returned slice aliases the source of the decoder.
+func (d *etfDecoder) text() ([]byte, error) {
+	tag, err := d.peekTag()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case etfBinary:
+		d.pos++
+		n, err := d.readLen(4)
+		if err != nil {
+			return nil, err
+		}
+		return d.read(n)
+	case etfString:
+		d.pos++
+		n, err := d.readLen(2)
+		if err != nil {
+			return nil, err
+		}
+		return d.read(n)
+	case etfNil:
+		// the empty byte list
+		d.pos++
+		return nil, nil
+	}
+	if name, size, ok := d.atomName(); ok {
+		d.pos += size
+		return name, nil
+	}
+	return nil, errors.New("goda: etf: expected a string, got term " + strconv.Itoa(int(tag)))
+}
+
+// uintOrText consumes an integer term, or a binary holding a decimal integer,
+// as the ones snowflakes and permissions are sent as.
+func (d *etfDecoder) uintOrText() (uint64, error) {
+	tag, err := d.peekTag()
+	if err != nil {
+		return 0, err
+	}
+	if tag == etfBinary || tag == etfString {
+		b, err := d.text()
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseUint(BytesToString(b), 10, 64)
+	}
+	v, negative, err := d.integer()
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		return 0, errETFOverflow
+	}
+	return v, nil
+}
+
+// mapHeader consumes the header of a map term and returns its number of pairs.
+func (d *etfDecoder) mapHeader() (int, error) {
+	tag, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	if tag[0] != etfMap {
+		d.pos--
+		return 0, errors.New("goda: etf: expected a map, got term " + strconv.Itoa(int(tag[0])))
+	}
+	return d.readLen(4)
+}
+
+// listHeader consumes the header of a list or tuple term and returns its
+// number of elements, tail is true if a list tail follows the elements.
+func (d *etfDecoder) listHeader() (n int, tail bool, err error) {
+	tag, err := d.read(1)
+	if err != nil {
+		return 0, false, err
+	}
+	switch tag[0] {
+	case etfNil:
+		return 0, false, nil
+	case etfList:
+		n, err := d.readLen(4)
+		return n, true, err
+	case etfSmallTuple:
+		n, err := d.readLen(1)
+		return n, false, err
+	case etfLargeTuple:
+		n, err := d.readLen(4)
+		return n, false, err
+	}
+	d.pos--
+	return 0, false, errors.New("goda: etf: expected a list, got term " + strconv.Itoa(int(tag[0])))
+}
+
+// listTail consumes the tail of a list, which must be the empty list.
+func (d *etfDecoder) listTail() error {
+	tail, err := d.read(1)
+	if err != nil {
+		return err
+	}
+	if tail[0] != etfNil {
+		return errETFImproper
+	}
+	return nil
+}
+
+// skip consumes the next term.
+func (d *etfDecoder) skip() error {
+	tag, err := d.read(1)
+	if err != nil {
+		return err
+	}
+
+	var n int
+	switch tag[0] {
+	case etfSmallInteger:
+		_, err = d.read(1)
+	case etfInteger:
+		_, err = d.read(4)
+	case etfNewFloat:
+		_, err = d.read(8)
+	case etfFloat:
+		_, err = d.read(31)
+	case etfAtom, etfAtomUTF8:
+		if n, err = d.readLen(2); err == nil {
+			_, err = d.read(n)
+		}
+	case etfSmallAtom, etfSmallAtomUTF8:
+		if n, err = d.readLen(1); err == nil {
+			_, err = d.read(n)
+		}
+	case etfString:
+		if n, err = d.readLen(2); err == nil {
+			_, err = d.read(n)
+		}
+	case etfBinary:
+		if n, err = d.readLen(4); err == nil {
+			_, err = d.read(n)
+		}
+	case etfSmallBig:
+		if n, err = d.readLen(1); err == nil {
+			_, err = d.read(n + 1)
+		}
+	case etfLargeBig:
+		if n, err = d.readLen(4); err == nil {
+			_, err = d.read(n + 1)
+		}
+	case etfNil:
+	case etfSmallTuple, etfLargeTuple, etfList, etfMap:
+		size := 4
+		if tag[0] == etfSmallTuple {
+			size = 1
+		}
+		if n, err = d.readLen(size); err != nil {
+			return err
+		}
+		switch tag[0] {
+		case etfList:
+			n++ // tail
+		case etfMap:
+			n *= 2
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		for range n {
+			if err := d.skip(); err != nil {
+				return err
+			}
+		}
+		d.depth--
+	default:
+		return errors.New("goda: etf: unsupported term tag " + strconv.Itoa(int(tag[0])))
+	}
+	return err
+}
+
+// anyValue consumes the next term as the value encoding/json decodes into an empty interface.
+func (d *etfDecoder) anyValue() (any, error) {
+	tag, err := d.peekTag()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case etfSmallInteger, etfInteger, etfSmallBig, etfLargeBig, etfNewFloat, etfFloat:
+		return d.number()
+	case etfBinary:
+		b, err := d.text()
+		return string(b), err
+	case etfMap:
+		n, err := d.mapHeader()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		m := make(map[string]any, n)
+		for range n {
+			key, err := d.textOrInt()
+			if err != nil {
+				return nil, err
+			}
+			if m[key], err = d.anyValue(); err != nil {
+				return nil, err
+			}
+		}
+		d.depth--
+		return m, nil
+	case etfString:
+		b, err := d.text()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, len(b))
+		for i, c := range b {
+			list[i] = float64(c)
+		}
+		return list, nil
+	case etfNil, etfList, etfSmallTuple, etfLargeTuple:
+		n, tail, err := d.listHeader()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		list := make([]any, n)
+		for i := range list {
+			if list[i], err = d.anyValue(); err != nil {
+				return nil, err
+			}
+		}
+		d.depth--
+		if tail {
+			return list, d.listTail()
+		}
+		return list, nil
+	}
+
+	name, _, ok := d.atomName()
+	if !ok {
+		return nil, errors.New("goda: etf: unsupported term tag " + strconv.Itoa(int(tag)))
+	}
+	switch string(name) {
+	case "nil", "null":
+		d.nilAtom()
+		return nil, nil
+	case "true", "false":
+		d.text()
+		return string(name) == "true", nil
+	}
+	b, err := d.text()
+	return string(b), err
+}
+
+// textOrInt consumes a text or integer term as a string, formatting integers in decimal.
+func (d *etfDecoder) textOrInt() (string, error) {
+	tag, err := d.peekTag()
+	if err != nil {
+		return "", err
+	}
+	switch tag {
+	case etfSmallInteger, etfInteger, etfSmallBig, etfLargeBig:
+		v, negative, err := d.integer()
+		if negative {
+			return "-" + strconv.FormatUint(v, 10), err
+		}
+		return strconv.FormatUint(v, 10), err
+	}
+	b, err := d.text()
+	return string(b), err
+}
+
+/*****************************
+ *    Typed decoding
+ *****************************/
+
+// etfDecodeFunc decodes the next term into v, which must be settable.
+type etfDecodeFunc func(d *etfDecoder, v reflect.Value) error
+
+var (
+	etfDecoders         sync.Map // reflect.Type -> etfDecodeFunc
+	etfUnmarshalerType  = reflect.TypeFor[etfUnmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	rawMessageType      = reflect.TypeFor[json.RawMessage]()
+)
+
+// etfDecoderFor returns the decode function of t, building it once.
+func etfDecoderFor(t reflect.Type) etfDecodeFunc {
+	if fn, ok := etfDecoders.Load(t); ok {
+		return fn.(etfDecodeFunc)
+	}
+
+	// recursive types use the function being built through this indirection
+	var (
+		wg sync.WaitGroup
+		fn etfDecodeFunc
+	)
+	wg.Add(1)
+	indirect, loaded := etfDecoders.LoadOrStore(t, etfDecodeFunc(func(d *etfDecoder, v reflect.Value) error {
+		wg.Wait()
+		return fn(d, v)
+	}))
+	if loaded {
+		return indirect.(etfDecodeFunc)
+	}
+
+	fn = newETFDecodeFunc(t)
+	wg.Done()
+	etfDecoders.Store(t, fn)
+	return fn
+}
+
+// newETFDecodeFunc builds the decode function of t, handling the nil atom
+// like encoding/json handles null.
+func newETFDecodeFunc(t reflect.Type) etfDecodeFunc {
+	decode := newETFValueFunc(t)
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+		return func(d *etfDecoder, v reflect.Value) error {
+			if d.nilAtom() {
+				v.SetZero()
+				return nil
+			}
+			return decode(d, v)
+		}
+	}
+	return func(d *etfDecoder, v reflect.Value) error {
+		if d.nilAtom() {
+			return nil
+		}
+		return decode(d, v)
+	}
+}
+
+func newETFValueFunc(t reflect.Type) etfDecodeFunc {
+	if reflect.PointerTo(t).Implements(etfUnmarshalerType) {
+		return func(d *etfDecoder, v reflect.Value) error {
+			return v.Addr().Interface().(etfUnmarshaler).unmarshalETF(d)
+		}
+	}
+	if t == rawMessageType {
+		return decodeETFRawMessage
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return decodeETFText
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return decodeETFBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decodeETFInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return decodeETFUint
+	case reflect.Float32, reflect.Float64:
+		return decodeETFFloat
+	case reflect.String:
+		return decodeETFString
+	case reflect.Slice:
+		return newETFSliceFunc(t)
+	case reflect.Array:
+		return newETFArrayFunc(t)
+	case reflect.Map:
+		return newETFMapFunc(t)
+	case reflect.Struct:
+		return newETFStructFunc(t)
+	case reflect.Pointer:
+		return newETFPointerFunc(t)
+	case reflect.Interface:
+		return newETFInterfaceFunc(t)
+	}
+	return func(d *etfDecoder, v reflect.Value) error {
+		return errors.New("goda: etf: unsupported type " + t.String())
+	}
+}
+
+func decodeETFBool(d *etfDecoder, v reflect.Value) error {
+	name, size, ok := d.atomName()
+	if !ok || (string(name) != "true" && string(name) != "false") {
+		tag, _ := d.peekTag()
+		return d.typeError(tag, v.Type())
+	}
+	d.pos += size
+	v.SetBool(string(name) == "true")
+	return nil
+}
+
+func decodeETFInt(d *etfDecoder, v reflect.Value) error {
+	u, negative, err := d.integer()
+	if err != nil {
+		return err
+	}
+	if u > math.MaxInt64 && !(negative && u == 1<<63) {
+		return errETFOverflow
+	}
+	i := int64(u)
+	if negative {
+		i = -i
+	}
+	if v.OverflowInt(i) {
+		return errETFOverflow
+	}
+	v.SetInt(i)
+	return nil
+}
+
+func decodeETFUint(d *etfDecoder, v reflect.Value) error {
+	u, negative, err := d.integer()
+	if err != nil {
+		return err
+	}
+	if negative || v.OverflowUint(u) {
+		return errETFOverflow
+	}
+	v.SetUint(u)
+	return nil
+}
+
+func decodeETFFloat(d *etfDecoder, v reflect.Value) error {
+	f, err := d.number()
+	if err != nil {
+		return err
+	}
+	if v.OverflowFloat(f) {
+		return errETFOverflow
+	}
+	v.SetFloat(f)
+	return nil
+}
+
+func decodeETFString(d *etfDecoder, v reflect.Value) error {
+	if name, _, ok := d.atomName(); ok && (string(name) == "true" || string(name) == "false") {
+		return errors.New("goda: etf: cannot decode a boolean into " + v.Type().String())
+	}
+	b, err := d.text()
+	if err != nil {
+		return err
+	}
+	v.SetString(string(b))
+	return nil
+}
+
+func decodeETFText(d *etfDecoder, v reflect.Value) error {
+	b, err := d.text()
+	if err != nil {
+		return err
+	}
+	return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(b)
+}
+
+// decodeETFRawMessage transcodes the term into JSON, json.RawMessage values
+// being decoded later with encoding/json.
+func decodeETFRawMessage(d *etfDecoder, v reflect.Value) error {
+	raw, err := d.appendJSON(nil)
+	if err != nil {
+		return err
+	}
+	v.SetBytes(raw)
+	return nil
+}
+
+func newETFPointerFunc(t reflect.Type) etfDecodeFunc {
+	elem := etfDecoderFor(t.Elem())
+	return func(d *etfDecoder, v reflect.Value) error {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return elem(d, v.Elem())
+	}
+}
+
+func newETFInterfaceFunc(t reflect.Type) etfDecodeFunc {
+	if decode := etfInterfaceDecoder(t); decode != nil {
+		return func(d *etfDecoder, v reflect.Value) error {
+			value, err := decode(d)
+			if err != nil {
+				return err
+			}
+			rv := reflect.ValueOf(value)
+			if !rv.Type().AssignableTo(t) {
+				return errors.New("goda: etf: cannot decode " + rv.Type().String() + " into " + t.String())
+			}
+			v.Set(rv)
+			return nil
+		}
+	}
+	if t.NumMethod() > 0 {
+		return func(d *etfDecoder, v reflect.Value) error {
+			return errors.New("goda: etf: cannot decode into interface " + t.String())
+		}
+	}
+	return func(d *etfDecoder, v reflect.Value) error {
+		value, err := d.anyValue()
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+}
+
+// setByte sets v, an element of a slice decoded from a byte list, to c.
+func setByte(v reflect.Value, c byte) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(int64(c)) {
+			return errETFOverflow
+		}
+		v.SetInt(int64(c))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(uint64(c))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(c))
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			return errors.New("goda: etf: cannot decode an integer into " + v.Type().String())
+		}
+		v.Set(reflect.ValueOf(float64(c)))
+	default:
+		return errors.New("goda: etf: cannot decode an integer into " + v.Type().String())
+	}
+	return nil
+}
+
+func newETFSliceFunc(t reflect.Type) etfDecodeFunc {
+	elem := etfDecoderFor(t.Elem())
+	return func(d *etfDecoder, v reflect.Value) error {
+		tag, err := d.peekTag()
+		if err != nil {
+			return err
+		}
+
+		// lists of small integers are sent as byte lists
+		var list []byte
+		n, tail := 0, false
+		if tag == etfString {
+			if list, err = d.text(); err != nil {
+				return err
+			}
+			n = len(list)
+		} else if n, tail, err = d.listHeader(); err != nil {
+			return err
+		}
+
+		// like encoding/json, elements are decoded into the existing ones
+		if n > v.Cap() {
+			grown := reflect.MakeSlice(t, v.Len(), n)
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+		oldLen := v.Len()
+		if v.IsNil() {
+			v.Set(reflect.MakeSlice(t, 0, 0))
+		}
+		v.SetLen(n)
+		for i := oldLen; i < n; i++ {
+			v.Index(i).SetZero()
+		}
+
+		if list != nil {
+			for i, c := range list {
+				if err := setByte(v.Index(i), c); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if err := d.enter(); err != nil {
+			return err
+		}
+		for i := range n {
+			if err := elem(d, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		d.depth--
+		if tail {
+			return d.listTail()
+		}
+		return nil
+	}
+}
+
+func newETFArrayFunc(t reflect.Type) etfDecodeFunc {
+	elem := etfDecoderFor(t.Elem())
+	return func(d *etfDecoder, v reflect.Value) error {
+		tag, err := d.peekTag()
+		if err != nil {
+			return err
+		}
+		if tag == etfString {
+			list, err := d.text()
+			if err != nil {
+				return err
+			}
+			for i := range v.Len() {
+				if i >= len(list) {
+					v.Index(i).SetZero()
+				} else if err := setByte(v.Index(i), list[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		n, tail, err := d.listHeader()
+		if err != nil {
+			return err
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		for i := range n {
+			if i >= v.Len() {
+				if err := d.skip(); err != nil {
+					return err
+				}
+			} else if err := elem(d, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		for i := n; i < v.Len(); i++ {
+			v.Index(i).SetZero()
+		}
+		d.depth--
+		if tail {
+			return d.listTail()
+		}
+		return nil
+	}
+}
+
+func newETFMapFunc(t reflect.Type) etfDecodeFunc {
+	key := newETFMapKeyFunc(t.Key())
+	elem := etfDecoderFor(t.Elem())
+	return func(d *etfDecoder, v reflect.Value) error {
+		n, err := d.mapHeader()
+		if err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, n))
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		kv := reflect.New(t.Key()).Elem()
+		ev := reflect.New(t.Elem()).Elem()
+		for range n {
+			if err := key(d, kv); err != nil {
+				return err
+			}
+			ev.SetZero()
+			if err := elem(d, ev); err != nil {
+				return err
+			}
+			v.SetMapIndex(kv, ev)
+		}
+		d.depth--
+		return nil
+	}
+}
+
+// newETFMapKeyFunc decodes map keys the way encoding/json decodes object keys,
+// also accepting integer keys for integer types.
+func newETFMapKeyFunc(t reflect.Type) etfDecodeFunc {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return decodeETFText
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(d *etfDecoder, v reflect.Value) error {
+			key, err := d.textOrInt()
+			v.SetString(key)
+			return err
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(d *etfDecoder, v reflect.Value) error {
+			key, err := d.textOrInt()
+			if err != nil {
+				return err
+			}
+			i, err := strconv.ParseInt(key, 10, 64)
+			if err != nil || v.OverflowInt(i) {
+				return errors.New("goda: etf: invalid map key " + strconv.Quote(key) + " for " + t.String())
+			}
+			v.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(d *etfDecoder, v reflect.Value) error {
+			key, err := d.textOrInt()
+			if err != nil {
+				return err
+			}
+			u, err := strconv.ParseUint(key, 10, 64)
+			if err != nil || v.OverflowUint(u) {
+				return errors.New("goda: etf: invalid map key " + strconv.Quote(key) + " for " + t.String())
+			}
+			v.SetUint(u)
+			return nil
+		}
+	}
+	return func(d *etfDecoder, v reflect.Value) error {
+		return errors.New("goda: etf: unsupported map key type " + t.String())
+	}
+}
+
+// etfField is a struct field decoded from the map key name.
+type etfField struct {
+	name   string
+	index  []int
+	tagged bool
+	decode etfDecodeFunc
+}
+
+func newETFStructFunc(t reflect.Type) etfDecodeFunc {
+	fields := etfStructFields(t)
+	byName := make(map[string]*etfField, len(fields))
+	for i := range fields {
+		byName[fields[i].name] = &fields[i]
+	}
+
+	return func(d *etfDecoder, v reflect.Value) error {
+		n, err := d.mapHeader()
+		if err != nil {
+			return err
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		for range n {
+			key, err := d.text()
+			if err != nil {
+				// integer keys never name a field
+				if err := d.skip(); err != nil {
+					return err
+				}
+				if err := d.skip(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			f := byName[string(key)]
+			if f == nil {
+				// like encoding/json, fall back to a case-insensitive match
+				for i := range fields {
+					if strings.EqualFold(fields[i].name, BytesToString(key)) {
+						f = &fields[i]
+						break
+					}
+				}
+			}
+			if f == nil {
+				if err := d.skip(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			fv, err := etfFieldByIndex(v, f.index)
+			if err != nil {
+				return err
+			}
+			if err := f.decode(d, fv); err != nil {
+				return err
+			}
+		}
+		d.depth--
+		return nil
+	}
+}
+
+// etfFieldByIndex returns the field of v at index, allocating the nil embedded
+// struct pointers on its way.
+func etfFieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return v, errors.New("goda: etf: cannot set embedded pointer to unexported struct " + v.Type().Elem().String())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// etfStructFields returns the fields of t decoded by name, following the
+// visibility rules of encoding/json for embedded structs.
+func etfStructFields(t reflect.Type) []etfField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var all []etfField
+	depth := map[string]int{} // depth of each name in all
+	current, next := []embedded{}, []embedded{{typ: t}}
+	visited := map[reflect.Type]bool{}
+	for level := 0; len(next) > 0; level++ {
+		current, next = next, nil
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := range e.typ.NumField() {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				index := append(append([]int(nil), e.index...), i)
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: ft, index: index})
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+				}
+				if d, ok := depth[name]; ok && d < level {
+					continue // hidden by a shallower field
+				}
+				depth[name] = level
+				all = append(all, etfField{name: name, index: index, tagged: tagged})
+			}
+		}
+	}
+
+	// fields of the same name at the same depth cancel out, unless a single one is tagged
+	fields := make([]etfField, 0, len(all))
+	for _, f := range all {
+		var same []etfField
+		for _, other := range all {
+			if other.name == f.name && len(other.index) == len(f.index) {
+				same = append(same, other)
+			}
+		}
+		if len(same) > 1 {
+			tagged := 0
+			for _, other := range same {
+				if other.tagged {
+					tagged++
+				}
+			}
+			if tagged != 1 || !f.tagged {
+				continue
+			}
+		}
+		f.decode = etfDecoderFor(t.FieldByIndex(f.index).Type)
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+/*****************************
+ *    JSON transcoding
+ *****************************/
+
+// appendJSON consumes the next term and appends its JSON equivalent to dst.
+func (d *etfDecoder) appendJSON(dst []byte) ([]byte, error) {
+	tag, err := d.peekTag()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case etfSmallInteger, etfInteger, etfSmallBig, etfLargeBig:
+		return d.appendJSONInteger(dst)
+	case etfNewFloat, etfFloat:
+		f, err := d.float()
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, errors.New("goda: etf: unsupported float value")
+		}
+		return strconv.AppendFloat(dst, f, 'g', -1, 64), nil
+	case etfBinary:
+		b, err := d.text()
+		if err != nil {
+			return nil, err
+		}
+		return appendJSONString(dst, b), nil
+	case etfString:
+		// a list of small integers, not a text string
+		b, err := d.text()
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, '[')
+		for i, c := range b {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = strconv.AppendUint(dst, uint64(c), 10)
+		}
+		return append(dst, ']'), nil
+	case etfNil, etfList, etfSmallTuple, etfLargeTuple:
+		n, tail, err := d.listHeader()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		dst = append(dst, '[')
+		for i := range n {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = d.appendJSON(dst); err != nil {
+				return nil, err
+			}
+		}
+		d.depth--
+		if tail {
+			if err := d.listTail(); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, ']'), nil
+	case etfMap:
+		n, err := d.mapHeader()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		dst = append(dst, '{')
+		for i := range n {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			key, err := d.textOrInt()
+			if err != nil {
+				return nil, err
+			}
+			dst = appendJSONString(dst, []byte(key))
+			dst = append(dst, ':')
+			if dst, err = d.appendJSON(dst); err != nil {
+				return nil, err
+			}
+		}
+		d.depth--
+		return append(dst, '}'), nil
+	}
+
+	name, _, ok := d.atomName()
+	if !ok {
+		return nil, errors.New("goda: etf: unsupported term tag " + strconv.Itoa(int(tag)))
+	}
+	d.text()
+	switch string(name) {
+	case "nil", "null":
+		return append(dst, "null"...), nil
+	case "true", "false":
+		return append(dst, name...), nil
+	}
+	return appendJSONString(dst, name), nil
+}
+
+// appendJSONInteger consumes an integer term and appends it as a JSON number.
+func (d *etfDecoder) appendJSONInteger(dst []byte) ([]byte, error) {
+	start := d.pos
+	v, negative, err := d.integer()
+	if err == nil {
+		if negative {
+			dst = append(dst, '-')
+		}
+		return strconv.AppendUint(dst, v, 10), nil
+	}
+	if err != errETFOverflow {
+		return nil, err
+	}
+
+	// integers beyond 64 bits
+	d.pos = start
+	tag, _ := d.read(1)
+	size := 1
+	if tag[0] == etfLargeBig {
+		size = 4
+	}
+	n, _ := d.readLen(size)
+	b, _ := d.read(n + 1)
+	be := make([]byte, n)
+	for i := range n {
+		be[i] = b[n-i]
+	}
+	i := new(big.Int).SetBytes(be)
+	if b[0] != 0 {
+		i.Neg(i)
+	}
+	return i.Append(dst, 10), nil
+}
+
+// appendJSONString appends s as a quoted JSON string,
+// replacing invalid UTF-8 with the replacement character.
+func appendJSONString(dst []byte, s []byte) []byte {
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRune(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "�"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+/*****************************
+ *        Encoding
+ *****************************/
+
+// etfEncoder transcodes JSON into an ETF term.
+type etfEncoder struct {
+	dec *json.Decoder
+	dst []byte
+}
+
+// jsonToETF encodes the JSON document src as an ETF term.
+//
+// Strings and object keys are encoded as binaries, null, true and false as atoms.
+func jsonToETF(src []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+
+	e := etfEncoder{dec: dec, dst: make([]byte, 1, len(src))}
+	e.dst[0] = etfVersion
+	if err := e.value(); err != nil {
+		return nil, err
+	}
+	return e.dst, nil
+}
+
+// value encodes the next JSON value.
+func (e *etfEncoder) value() error {
+	tok, err := e.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return e.object()
+		case '[':
+			return e.array()
+		}
+		return errETFInvalidJSON
+	case string:
+		e.binary(v)
+	case json.Number:
+		return e.number(v)
+	case bool:
+		if v {
+			e.atom("true")
+		} else {
+			e.atom("false")
+		}
+	case nil:
+		e.atom("nil")
+	}
+	return nil
+}
+
+// object encodes the members of a JSON object as a map.
+func (e *etfEncoder) object() error {
+	e.dst = append(e.dst, etfMap, 0, 0, 0, 0)
+	at := len(e.dst) - 4
+
+	var n uint32
+	for e.dec.More() {
+		tok, err := e.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return errETFInvalidJSON
+		}
+		e.binary(key)
+		if err := e.value(); err != nil {
+			return err
+		}
+		n++
+	}
+	binary.BigEndian.PutUint32(e.dst[at:], n)
+
+	_, err := e.dec.Token() // '}'
+	return err
+}
+
+// array encodes the elements of a JSON array as a proper list.
+func (e *etfEncoder) array() error {
+	e.dst = append(e.dst, etfList, 0, 0, 0, 0)
+	at := len(e.dst) - 4
+
+	var n uint32
+	for e.dec.More() {
+		if err := e.value(); err != nil {
+			return err
+		}
+		n++
+	}
+
+	if n == 0 {
+		e.dst = append(e.dst[:at-1], etfNil)
+	} else {
+		binary.BigEndian.PutUint32(e.dst[at:], n)
+		e.dst = append(e.dst, etfNil)
+	}
+
+	_, err := e.dec.Token() // ']'
+	return err
+}
+
+// number encodes a JSON number using the smallest fitting integer term,
+// or a float term for non integers.
+func (e *etfEncoder) number(n json.Number) error {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		switch {
+		case i >= 0 && i <= math.MaxUint8:
+			e.dst = append(e.dst, etfSmallInteger, byte(i))
+		case i >= math.MinInt32 && i <= math.MaxInt32:
+			e.dst = append(e.dst, etfInteger)
+			e.dst = binary.BigEndian.AppendUint32(e.dst, uint32(int32(i)))
+		case i < 0:
+			e.smallBig(uint64(-i), true)
+		default:
+			e.smallBig(uint64(i), false)
+		}
+		return nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		e.smallBig(u, false)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return err
+	}
+	e.dst = append(e.dst, etfNewFloat)
+	e.dst = binary.BigEndian.AppendUint64(e.dst, math.Float64bits(f))
+	return nil
+}
+
+// smallBig encodes v as a small big integer.
+func (e *etfEncoder) smallBig(v uint64, negative bool) {
+	var digits [8]byte
+	n := 0
+	for ; v > 0; v >>= 8 {
+		digits[n] = byte(v)
+		n++
+	}
+	sign := byte(0)
+	if negative {
+		sign = 1
+	}
+	e.dst = append(e.dst, etfSmallBig, byte(n), sign)
+	e.dst = append(e.dst, digits[:n]...)
+}
+
+// binary encodes s as a binary.
+func (e *etfEncoder) binary(s string) {
+	e.dst = append(e.dst, etfBinary)
+	e.dst = binary.BigEndian.AppendUint32(e.dst, uint32(len(s)))
+	e.dst = append(e.dst, s...)
+}
+
+// atom encodes name as a small UTF-8 atom.
+func (e *etfEncoder) atom(name string) {
+	e.dst = append(e.dst, etfSmallAtomUTF8, byte(len(name)))
+	e.dst = append(e.dst, name...)
+}
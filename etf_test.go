@@ -0,0 +1,363 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+// Helpers
+
+// discordETF encodes a JSON gateway payload the way Discord does with the ETF
+// encoding: map keys are atoms, null is the nil atom and snowflakes are sent
+// as integers instead of strings.
+func discordETF(tb testing.TB, payload []byte) []byte {
+	tb.Helper()
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		tb.Fatalf("invalid corpus payload: %v", err)
+	}
+	return appendDiscordTerm([]byte{etfVersion}, v)
+}
+
+func appendDiscordTerm(dst []byte, v any) []byte {
+	switch v := v.(type) {
+	case map[string]any:
+		dst = append(dst, etfMap)
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(v)))
+		for key, e := range v {
+			dst = append(dst, etfSmallAtomUTF8, byte(len(key)))
+			dst = append(dst, key...)
+			dst = appendDiscordTerm(dst, e)
+		}
+	case []any:
+		if len(v) == 0 {
+			return append(dst, etfNil)
+		}
+		dst = append(dst, etfList)
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(v)))
+		for _, e := range v {
+			dst = appendDiscordTerm(dst, e)
+		}
+		dst = append(dst, etfNil)
+	case string:
+		if id, err := strconv.ParseUint(v, 10, 64); err == nil && len(v) >= 17 {
+			return appendDiscordTerm(dst, json.Number(strconv.FormatUint(id, 10)))
+		}
+		dst = append(dst, etfBinary)
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(v)))
+		dst = append(dst, v...)
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 32); err == nil {
+			if i >= 0 && i <= math.MaxUint8 {
+				return append(dst, etfSmallInteger, byte(i))
+			}
+			dst = append(dst, etfInteger)
+			return binary.BigEndian.AppendUint32(dst, uint32(int32(i)))
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			dst = append(dst, etfSmallBig, 8, 0)
+			return binary.LittleEndian.AppendUint64(dst, u)
+		}
+		f, _ := v.Float64()
+		dst = append(dst, etfNewFloat)
+		dst = binary.BigEndian.AppendUint64(dst, math.Float64bits(f))
+	case bool:
+		if v {
+			dst = append(dst, etfSmallAtomUTF8, 4, 't', 'r', 'u', 'e')
+		} else {
+			dst = append(dst, etfSmallAtomUTF8, 5, 'f', 'a', 'l', 's', 'e')
+		}
+	case nil:
+		dst = append(dst, etfSmallAtomUTF8, 3, 'n', 'i', 'l')
+	}
+	return dst
+}
+
+// decodeTypedEvent decodes event data into the type its handler uses.
+func decodeTypedEvent(tb testing.TB, name string, data []byte) any {
+	tb.Helper()
+
+	var v any
+	switch name {
+	case "READY":
+		v = &ReadyEvent{}
+	case "GUILD_CREATE":
+		v = &GatewayGuild{}
+	case "MESSAGE_CREATE":
+		v = &Message{}
+	case "INTERACTION_CREATE":
+		v = &InteractionCreateEvent{}
+	case "VOICE_STATE_UPDATE":
+		v = &VoiceState{}
+	default:
+		v = new(any)
+	}
+	if err := unmarshalEventData(data, v); err != nil {
+		tb.Fatalf("decoding %s: %v", name, err)
+	}
+	return v
+}
+
+// Tests
+
+func TestETF_CorpusMatchesJSON(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "etf", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("missing etf corpus: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var fromJSON gatewayPayload
+			if err := unmarshalEventData(raw, &fromJSON); err != nil {
+				t.Fatalf("json payload: %v", err)
+			}
+			var fromETF gatewayPayload
+			if err := unmarshalEventData(discordETF(t, raw), &fromETF); err != nil {
+				t.Fatalf("etf payload: %v", err)
+			}
+
+			if fromJSON.Op != fromETF.Op || fromJSON.S != fromETF.S || fromJSON.T != fromETF.T {
+				t.Fatalf("payload header mismatch: json=%d/%d/%q etf=%d/%d/%q",
+					fromJSON.Op, fromJSON.S, fromJSON.T, fromETF.Op, fromETF.S, fromETF.T)
+			}
+			if len(fromETF.D) == 0 || fromETF.D[0] != etfVersion {
+				t.Fatalf("etf payload data is not an etf term: %q", fromETF.D)
+			}
+
+			want := decodeTypedEvent(t, fromJSON.T, fromJSON.D)
+			got := decodeTypedEvent(t, fromETF.T, fromETF.D)
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("typed event mismatch\njson: %+v\netf:  %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestETF_DiscordTerms(t *testing.T) {
+	// #{op => 0, t => 'MESSAGE_CREATE', s => nil, d => #{id => 1210000000000000100, mentions => {}, nonce => -5}}
+	// as encoded by the Gateway: atom keys, big integer snowflake, tuple and nil atom.
+	term := []byte{
+		etfVersion, etfMap, 0, 0, 0, 4,
+		etfSmallAtomUTF8, 2, 'o', 'p', etfSmallInteger, 0,
+		etfSmallAtomUTF8, 1, 't', etfAtomUTF8, 0, 14,
+		'M', 'E', 'S', 'S', 'A', 'G', 'E', '_', 'C', 'R', 'E', 'A', 'T', 'E',
+		etfSmallAtomUTF8, 1, 's', etfSmallAtom, 3, 'n', 'i', 'l',
+		etfSmallAtomUTF8, 1, 'd', etfMap, 0, 0, 0, 3,
+		etfSmallAtomUTF8, 2, 'i', 'd', etfSmallBig, 8, 0, 0x64, 0x00, 0x39, 0xd2, 0x96, 0xc8, 0xca, 0x10,
+		etfSmallAtomUTF8, 8, 'm', 'e', 'n', 't', 'i', 'o', 'n', 's', etfSmallTuple, 0,
+		etfSmallAtomUTF8, 5, 'n', 'o', 'n', 'c', 'e', etfInteger, 0xff, 0xff, 0xff, 0xfb,
+	}
+
+	var payload gatewayPayload
+	if err := unmarshalEventData(term, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.Op != gatewayOpcodeDispatch || payload.T != "MESSAGE_CREATE" || payload.S != 0 {
+		t.Fatalf("unexpected payload header %d/%d/%q", payload.Op, payload.S, payload.T)
+	}
+
+	var msg Message
+	if err := unmarshalEventData(payload.D, &msg); err != nil {
+		t.Fatalf("message: %v", err)
+	}
+	if msg.ID != 1210000000000000100 {
+		t.Fatalf("expected snowflake 1210000000000000100 got %d", msg.ID)
+	}
+	if msg.Nonce != "-5" {
+		t.Fatalf("expected nonce -5 got %q", msg.Nonce)
+	}
+	if msg.Mentions == nil || len(msg.Mentions) != 0 {
+		t.Fatalf("expected empty mentions got %#v", msg.Mentions)
+	}
+}
+
+func TestETF_RawMessageTranscodedToJSON(t *testing.T) {
+	raw := []byte(`{"name":"user","type":6,"value":"1183752640271728651"}`)
+	var option ChatInputInteractionCommandOption
+	if err := unmarshalEventData(discordETF(t, raw), &option); err != nil {
+		t.Fatal(err)
+	}
+	if string(option.Value) != "1183752640271728651" {
+		t.Fatalf("expected the value as a json number got %s", option.Value)
+	}
+	if id := option.Snowflake(); id != 1183752640271728651 {
+		t.Fatalf("expected snowflake 1183752640271728651 got %d", id)
+	}
+}
+
+func TestETF_CompressedTerm(t *testing.T) {
+	inner, err := jsonToETF([]byte(`{"content":"compressed","embeds":[{"title":"a"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner = inner[1:] // strip version
+
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(inner)
+	zw.Close()
+
+	term := []byte{etfVersion, etfCompressed, 0, 0, 0, byte(len(inner))}
+	term = append(term, z.Bytes()...)
+
+	var msg Message
+	if err := unmarshalETF(term, &msg); err != nil {
+		t.Fatalf("unmarshalETF: %v", err)
+	}
+	if msg.Content != "compressed" || len(msg.Embeds) != 1 || msg.Embeds[0].Title != "a" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+}
+
+func TestETF_Truncated(t *testing.T) {
+	full, err := jsonToETF([]byte(`{"op":2,"d":{"token":"abc","intents":513,"shard":[0,1]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(full); i++ {
+		var payload gatewayPayload
+		if err := unmarshalETF(full[:i], &payload); err == nil {
+			t.Fatalf("expected error decoding %d/%d bytes", i, len(full))
+		}
+	}
+}
+
+func TestETF_TooDeep(t *testing.T) {
+	term := []byte{etfVersion}
+	for range etfMaxDepth + 1 {
+		term = append(term, etfList, 0, 0, 0, 1)
+	}
+	term = append(term, etfNil)
+	for range etfMaxDepth + 1 {
+		term = append(term, etfNil)
+	}
+
+	var v any
+	if err := unmarshalETF(term, &v); err != errETFTooDeep {
+		t.Fatalf("expected errETFTooDeep got %v", err)
+	}
+}
+
+func TestShard_ETFEncoding(t *testing.T) {
+	identified := make(chan map[string]any, 1)
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		hello := discordETF(t, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		wsutil.WriteServerMessage(conn, ws.OpBinary, hello)
+
+		msg, op, err := wsutil.ReadClientData(conn)
+		if err != nil {
+			return
+		}
+		if op != ws.OpBinary {
+			identified <- nil
+			return
+		}
+		var identify map[string]any
+		if err := unmarshalETF(msg, &identify); err != nil {
+			identified <- nil
+			return
+		}
+		identified <- identify
+
+		ready := discordETF(t, []byte(`{"op":0,"s":1,"t":"READY","d":{"session_id":"etf-session","resume_gateway_url":"wss://resume","guilds":[]}}`))
+		wsutil.WriteServerMessage(conn, ws.OpBinary, ready)
+		io.Copy(io.Discard, conn)
+	})
+
+	s := newTestShard(url, nil)
+	s.encoding = GatewayEncodingETF
+	if query := s.gatewayQuery("wss://gateway.discord.gg"); !strings.Contains(query, "&encoding=etf") {
+		t.Fatalf("expected etf encoding in the gateway query, got %s", query)
+	}
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer s.Shutdown()
+
+	select {
+	case identify := <-identified:
+		if identify == nil || identify["op"] != float64(gatewayOpcodeIdentify) {
+			t.Fatalf("expected an etf identify payload, got %v", identify)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("identify not received")
+	}
+
+	select {
+	case <-s.readyCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("etf READY not handled")
+	}
+	if s.sessionID != "etf-session" {
+		t.Fatalf("expected session etf-session, got %q", s.sessionID)
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDecodeMessageCreate(b *testing.B) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "etf", "message_create.json"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, encoding := range []struct {
+		name    string
+		payload []byte
+	}{
+		{"json", raw},
+		{"etf", discordETF(b, raw)},
+	} {
+		b.Run(encoding.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(encoding.payload)))
+			for range b.N {
+				var payload gatewayPayload
+				if err := unmarshalEventData(encoding.payload, &payload); err != nil {
+					b.Fatal(err)
+				}
+				var msg Message
+				if err := unmarshalEventData(payload.D, &msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
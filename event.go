@@ -18,6 +18,7 @@ import "encoding/json"
 // ReadyCreateEvent Shard is ready
 type ReadyEvent struct {
 	ShardsID int // shard that dispatched this event
+	User     User
 	Guilds   []Guild
 }
 
@@ -25,6 +26,9 @@ type ReadyEvent struct {
 type GuildCreateEvent struct {
 	ShardsID int // shard that dispatched this event
 	Guild    GatewayGuild
+	// Joined is true if the bot joined the guild, false if the guild became available
+	// (lazy loaded after READY, or back from an outage).
+	Joined bool
 }
 
 // MessageCreateEvent Message was created
@@ -63,6 +67,16 @@ func (c *InteractionCreateEvent) UnmarshalJSON(buf []byte) error {
 	return err
 }
 
+var _ etfUnmarshaler = (*InteractionCreateEvent)(nil)
+
+func (c *InteractionCreateEvent) unmarshalETF(d *etfDecoder) error {
+	interaction, err := unmarshalInteractionETF(d)
+	if err == nil {
+		c.Interaction = interaction
+	}
+	return err
+}
+
 // VoiceStateUpdateEvent VoiceState was updated
 type VoiceStateUpdateEvent struct {
 	ShardsID int // shard that dispatched this event
@@ -70,4 +84,120 @@ type VoiceStateUpdateEvent struct {
 	NewState VoiceState
 }
 
+// VoiceServerUpdateEvent Guild's voice server was updated, or a voice channel was joined
+type VoiceServerUpdateEvent struct {
+	ShardsID int       // shard that dispatched this event
+	Token    string    `json:"token"`
+	GuildID  Snowflake `json:"guild_id"`
+	Endpoint string    `json:"endpoint"` // empty if the voice server is not available yet
+}
+
+// GuildMembersChunkEvent Guild members were sent in response to a Request Guild Members
+type GuildMembersChunkEvent struct {
+	ShardsID   int         // shard that dispatched this event
+	GuildID    Snowflake   `json:"guild_id"`
+	Members    []Member    `json:"members"`
+	ChunkIndex int         `json:"chunk_index"`
+	ChunkCount int         `json:"chunk_count"`
+	NotFound   []Snowflake `json:"not_found"`
+	Presences  []Presence  `json:"presences"`
+	Nonce      string      `json:"nonce"`
+}
+
+// GuildSoundboardSoundCreateEvent Guild soundboard sound was created
+type GuildSoundboardSoundCreateEvent struct {
+	ShardsID int // shard that dispatched this event
+	Sound    SoundBoardSound
+}
+
+// GuildSoundboardSoundUpdateEvent Guild soundboard sound was updated
+type GuildSoundboardSoundUpdateEvent struct {
+	ShardsID int // shard that dispatched this event
+	OldSound SoundBoardSound
+	NewSound SoundBoardSound
+}
+
+// GuildSoundboardSoundDeleteEvent Guild soundboard sound was deleted
+type GuildSoundboardSoundDeleteEvent struct {
+	ShardsID int             // shard that dispatched this event
+	Sound    SoundBoardSound // only SoundID and GuildID are set if the sound was not cached
+}
+
+// GuildSoundboardSoundsUpdateEvent Guild soundboard sounds were updated
+type GuildSoundboardSoundsUpdateEvent struct {
+	ShardsID int               // shard that dispatched this event
+	GuildID  Snowflake         `json:"guild_id"`
+	Sounds   []SoundBoardSound `json:"soundboard_sounds"`
+}
+
+// SoundboardSoundsEvent Guild soundboard sounds were sent in response to a Request Soundboard Sounds
+type SoundboardSoundsEvent struct {
+	ShardsID int               // shard that dispatched this event
+	GuildID  Snowflake         `json:"guild_id"`
+	Sounds   []SoundBoardSound `json:"soundboard_sounds"`
+}
+
+// ShardErrorEvent Shard stopped on an error it cannot recover from, generated by goda
+type ShardErrorEvent struct {
+	ShardsID int   // shard that stopped
+	Err      error // error that stopped the shard, e.g. *GatewayCloseError
+}
+
+// ShardReadyEvent Shard received every guild of READY, or timed out waiting for them
+//
+// This event is generated by goda, not received from the Gateway.
+type ShardReadyEvent struct {
+	ShardsID int // shard that dispatched this event
+	// UnavailableGuilds are the guilds still unavailable when the shard timed out waiting.
+	UnavailableGuilds []Snowflake
+}
+
+// ClientReadyEvent Every shard of the client is ready
+//
+// This event is generated by goda, not received from the Gateway.
+type ClientReadyEvent struct {
+	ShardsID int // last shard that became ready
+}
+
+// ShardConnectEvent Shard opened a Gateway connection
+//
+// This event is generated by goda, not received from the Gateway.
+type ShardConnectEvent struct {
+	ShardsID int // shard that dispatched this event
+}
+
+// ShardDisconnectEvent Shard lost or closed its Gateway connection
+//
+// This event is generated by goda, not received from the Gateway.
+type ShardDisconnectEvent struct {
+	ShardsID int // shard that dispatched this event
+	// Code is the close code sent by the Gateway, 0 if the connection closed without one.
+	Code GatewayCloseEventCode
+	// Err is the reason of the disconnect, nil when the shard was shut down.
+	Err error
+}
+
+// ShardReconnectingEvent Shard is attempting to reconnect
+//
+// This event is generated by goda, not received from the Gateway.
+type ShardReconnectingEvent struct {
+	ShardsID int // shard that dispatched this event
+	Attempt  int // reconnect attempt, starting at 1
+}
+
+// ShardResumedEvent Shard resumed its session
+//
+// This event is generated by goda, not received from the Gateway.
+type ShardResumedEvent struct {
+	ShardsID int // shard that dispatched this event
+}
+
+// InvalidSessionEvent Gateway invalidated the session of a shard
+//
+// This event is generated by goda, not received from the Gateway.
+type InvalidSessionEvent struct {
+	ShardsID  int  // shard that dispatched this event
+	Resumable bool // whether the shard resumes the session instead of identifying again
+}
+
 // TODO: add other events
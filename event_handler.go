@@ -13,7 +13,10 @@
 
 package goda
 
-import "encoding/json"
+import (
+	"slices"
+	"sync"
+)
 
 /*****************************
  *   READY Handler
@@ -28,7 +31,7 @@ type readyHandlers struct {
 // handleEvent parses the READY event data and calls each registered handler.
 func (h *readyHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
 	evt := ReadyEvent{ShardsID: shardID}
-	if err := json.Unmarshal(data, &evt); err != nil {
+	if err := unmarshalEventData(data, &evt); err != nil {
 		h.logger.Error("readyHandlers: Failed parsing event data")
 		return
 	}
@@ -61,9 +64,15 @@ type guildCreateHandlers struct {
 
 // handleEvent parses the GUILD_CREATE event data and calls each registered handler.
 func (h *guildCreateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
-	evt := GuildCreateEvent{ShardsID: shardID}
+	h.handleGuildCreate(cache, shardID, data, false)
+}
+
+// handleGuildCreate is handleEvent for a GUILD_CREATE classified by the shard,
+// joined reports if the bot joined the guild.
+func (h *guildCreateHandlers) handleGuildCreate(cache CacheManager, shardID int, data []byte, joined bool) {
+	evt := GuildCreateEvent{ShardsID: shardID, Joined: joined}
 
-	if err := json.Unmarshal(data, &evt.Guild); err != nil {
+	if err := unmarshalEventData(data, &evt.Guild); err != nil {
 		h.logger.Error("guildCreateHandlers: Failed parsing event data")
 		return
 	}
@@ -93,6 +102,12 @@ func (h *guildCreateHandlers) handleEvent(cache CacheManager, shardID int, data
 			cache.PutVoiceState(evt.Guild.VoiceStates[i])
 		}
 	}
+	if flags.Has(CacheFlagSoundboardSounds) {
+		for i := range len(evt.Guild.SoundboardSounds) {
+			evt.Guild.SoundboardSounds[i].GuildID = evt.Guild.ID
+			cache.PutSoundboardSound(evt.Guild.SoundboardSounds[i])
+		}
+	}
 
 	for _, handler := range h.handlers {
 		handler(evt)
@@ -120,7 +135,7 @@ type messageCreateHandlers struct {
 func (h *messageCreateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
 	evt := MessageCreateEvent{ShardsID: shardID}
 
-	if err := json.Unmarshal(data, &evt.Message); err != nil {
+	if err := unmarshalEventData(data, &evt.Message); err != nil {
 		h.logger.Error("messageCreateHandlers: Failed parsing event data")
 		return
 	}
@@ -154,7 +169,7 @@ type messageDeleteHandlers struct {
 // handleEvent parses the MESSAGE_DELETE event data and calls each registered handler.
 func (h *messageDeleteHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
 	evt := MessageDeleteEvent{ShardsID: shardID}
-	if err := json.Unmarshal(data, &evt.Message); err != nil {
+	if err := unmarshalEventData(data, &evt.Message); err != nil {
 		h.logger.Error("messageDeleteHandlers: Failed parsing event data")
 		return
 	}
@@ -189,7 +204,7 @@ type messageUpdateHandlers struct {
 // handleEvent parses the MESSAGE_UPDATE event data and calls each registered handler.
 func (h *messageUpdateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
 	evt := MessageUpdateEvent{ShardsID: shardID}
-	if err := json.Unmarshal(data, &evt.NewMessage); err != nil {
+	if err := unmarshalEventData(data, &evt.NewMessage); err != nil {
 		h.logger.Error("messageUpdateHandlers: Failed parsing event data")
 		return
 	}
@@ -234,7 +249,7 @@ type interactionCreateHandlers struct {
 // handleEvent parses the INTERACTION_CREATE event data and calls each registered handler.
 func (h *interactionCreateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
 	evt := InteractionCreateEvent{ShardsID: shardID}
-	if err := json.Unmarshal(data, &evt); err != nil {
+	if err := unmarshalEventData(data, &evt); err != nil {
 		h.logger.Error("interactionCreateHandlers: Failed parsing event data")
 		return
 	}
@@ -264,7 +279,7 @@ type voiceStateUpdateHandlers struct {
 // handleEvent parses the VOICE_STATE_UPDATE event data and calls each registered handler.
 func (h *voiceStateUpdateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
 	evt := VoiceStateUpdateEvent{ShardsID: shardID}
-	if err := json.Unmarshal(data, &evt.NewState); err != nil {
+	if err := unmarshalEventData(data, &evt.NewState); err != nil {
 		h.logger.Error("voiceStateCreateHandlers: Failed parsing event data")
 		return
 	}
@@ -291,3 +306,448 @@ func (h *voiceStateUpdateHandlers) handleEvent(cache CacheManager, shardID int,
 func (h *voiceStateUpdateHandlers) addHandler(handler any) {
 	h.handlers = append(h.handlers, handler.(func(VoiceStateUpdateEvent)))
 }
+
+/*****************************
+ * VOICE_SERVER_UPDATE Handler
+ *****************************/
+
+// voiceServerUpdateHandlers manages all registered handlers for VOICE_SERVER_UPDATE events.
+type voiceServerUpdateHandlers struct {
+	logger   Logger
+	handlers []func(VoiceServerUpdateEvent)
+}
+
+// handleEvent parses the VOICE_SERVER_UPDATE event data and calls each registered handler.
+func (h *voiceServerUpdateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
+	evt := VoiceServerUpdateEvent{ShardsID: shardID}
+	if err := unmarshalEventData(data, &evt); err != nil {
+		h.logger.Error("voiceServerUpdateHandlers: Failed parsing event data")
+		return
+	}
+
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
+
+// addHandler registers a new VOICE_SERVER_UPDATE handler function.
+//
+// This method is not thread-safe.
+func (h *voiceServerUpdateHandlers) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(VoiceServerUpdateEvent)))
+}
+
+/*****************************
+ * GUILD_MEMBERS_CHUNK Handler
+ *****************************/
+
+// guildMembersRequest collects the chunks answering a single Request Guild Members.
+type guildMembersRequest struct {
+	shardID  int // shard the request was sent on
+	mu       sync.Mutex
+	members  []Member
+	chunks   []bool // received chunk indexes, sized by the chunk count of the first chunk
+	received int
+	err      error         // failure of the request, set before done is closed
+	done     chan struct{} // closed once every chunk was received or the request failed
+}
+
+// add stores the members of a chunk, closing done once every chunk was received.
+//
+// A chunk index out of range or received twice fails the request with ErrInvalidMembersChunk.
+func (r *guildMembersRequest) add(evt GuildMembersChunkEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished() {
+		return
+	}
+
+	if r.chunks == nil && evt.ChunkCount > 0 {
+		r.chunks = make([]bool, evt.ChunkCount)
+	}
+	if evt.ChunkCount != len(r.chunks) || evt.ChunkIndex < 0 || evt.ChunkIndex >= len(r.chunks) || r.chunks[evt.ChunkIndex] {
+		r.finish(ErrInvalidMembersChunk)
+		return
+	}
+
+	r.chunks[evt.ChunkIndex] = true
+	r.members = append(r.members, evt.Members...)
+	r.received++
+	if r.received == len(r.chunks) {
+		r.finish(nil)
+	}
+}
+
+// fail completes the request with err if it is still waiting for chunks.
+func (r *guildMembersRequest) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.finished() {
+		r.finish(err)
+	}
+}
+
+// finished reports if the request completed, r.mu must be held.
+func (r *guildMembersRequest) finished() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish completes the request with err, r.mu must be held.
+func (r *guildMembersRequest) finish(err error) {
+	r.err = err
+	close(r.done)
+}
+
+// guildMembersChunkHandlers manages all registered handlers for GUILD_MEMBERS_CHUNK events,
+// and the pending member requests waiting for their chunks.
+type guildMembersChunkHandlers struct {
+	logger   Logger
+	handlers []func(GuildMembersChunkEvent)
+	requests sync.Map // nonce -> *guildMembersRequest
+}
+
+// handleEvent parses the GUILD_MEMBERS_CHUNK event data and calls each registered handler.
+func (h *guildMembersChunkHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
+	evt := GuildMembersChunkEvent{ShardsID: shardID}
+	if err := unmarshalEventData(data, &evt); err != nil {
+		h.logger.Error("guildMembersChunkHandlers: Failed parsing event data")
+		return
+	}
+
+	for i := range len(evt.Members) {
+		evt.Members[i].GuildID = evt.GuildID
+	}
+
+	if cache.Flags().Has(CacheFlagMembers) {
+		for i := range len(evt.Members) {
+			cache.PutMember(evt.Members[i])
+		}
+	}
+
+	if evt.Nonce != "" {
+		if req, ok := h.requests.Load(evt.Nonce); ok {
+			req.(*guildMembersRequest).add(evt)
+		}
+	}
+
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
+
+// addHandler registers a new GUILD_MEMBERS_CHUNK handler function.
+//
+// This method is not thread-safe.
+func (h *guildMembersChunkHandlers) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(GuildMembersChunkEvent)))
+}
+
+// track registers a pending member request identified by nonce, sent on the given shard.
+func (h *guildMembersChunkHandlers) track(nonce string, shardID int) *guildMembersRequest {
+	req := &guildMembersRequest{shardID: shardID, done: make(chan struct{})}
+	h.requests.Store(nonce, req)
+	return req
+}
+
+// failShard fails the pending member requests sent on the shard with err.
+//
+// Called when the shard disconnects, the chunks it did not receive yet may be lost.
+func (h *guildMembersChunkHandlers) failShard(shardID int, err error) {
+	h.requests.Range(func(_, value any) bool {
+		if req := value.(*guildMembersRequest); req.shardID == shardID {
+			req.fail(err)
+		}
+		return true
+	})
+}
+
+// untrack removes a pending member request.
+func (h *guildMembersChunkHandlers) untrack(nonce string) {
+	h.requests.Delete(nonce)
+}
+
+/*****************************
+ * GUILD_SOUNDBOARD_SOUND_CREATE Handler
+ *****************************/
+
+// guildSoundboardSoundCreateHandlers manages all registered handlers for GUILD_SOUNDBOARD_SOUND_CREATE events.
+type guildSoundboardSoundCreateHandlers struct {
+	logger   Logger
+	handlers []func(GuildSoundboardSoundCreateEvent)
+}
+
+// handleEvent parses the GUILD_SOUNDBOARD_SOUND_CREATE event data and calls each registered handler.
+func (h *guildSoundboardSoundCreateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
+	evt := GuildSoundboardSoundCreateEvent{ShardsID: shardID}
+	if err := unmarshalEventData(data, &evt.Sound); err != nil {
+		h.logger.Error("guildSoundboardSoundCreateHandlers: Failed parsing event data")
+		return
+	}
+
+	if cache.Flags().Has(CacheFlagSoundboardSounds) {
+		cache.PutSoundboardSound(evt.Sound)
+	}
+
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
+
+// addHandler registers a new GUILD_SOUNDBOARD_SOUND_CREATE handler function.
+//
+// This method is not thread-safe.
+func (h *guildSoundboardSoundCreateHandlers) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(GuildSoundboardSoundCreateEvent)))
+}
+
+/*****************************
+ * GUILD_SOUNDBOARD_SOUND_UPDATE Handler
+ *****************************/
+
+// guildSoundboardSoundUpdateHandlers manages all registered handlers for GUILD_SOUNDBOARD_SOUND_UPDATE events.
+type guildSoundboardSoundUpdateHandlers struct {
+	logger   Logger
+	handlers []func(GuildSoundboardSoundUpdateEvent)
+}
+
+// handleEvent parses the GUILD_SOUNDBOARD_SOUND_UPDATE event data and calls each registered handler.
+func (h *guildSoundboardSoundUpdateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
+	evt := GuildSoundboardSoundUpdateEvent{ShardsID: shardID}
+	if err := unmarshalEventData(data, &evt.NewSound); err != nil {
+		h.logger.Error("guildSoundboardSoundUpdateHandlers: Failed parsing event data")
+		return
+	}
+
+	if oldSound, ok := cache.GetSoundboardSound(evt.NewSound.GuildID, evt.NewSound.SoundID); ok {
+		evt.OldSound = oldSound
+	}
+
+	if cache.Flags().Has(CacheFlagSoundboardSounds) {
+		cache.PutSoundboardSound(evt.NewSound)
+	}
+
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
+
+// addHandler registers a new GUILD_SOUNDBOARD_SOUND_UPDATE handler function.
+//
+// This method is not thread-safe.
+func (h *guildSoundboardSoundUpdateHandlers) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(GuildSoundboardSoundUpdateEvent)))
+}
+
+/*****************************
+ * GUILD_SOUNDBOARD_SOUND_DELETE Handler
+ *****************************/
+
+// guildSoundboardSoundDeleteHandlers manages all registered handlers for GUILD_SOUNDBOARD_SOUND_DELETE events.
+type guildSoundboardSoundDeleteHandlers struct {
+	logger   Logger
+	handlers []func(GuildSoundboardSoundDeleteEvent)
+}
+
+// handleEvent parses the GUILD_SOUNDBOARD_SOUND_DELETE event data and calls each registered handler.
+func (h *guildSoundboardSoundDeleteHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
+	evt := GuildSoundboardSoundDeleteEvent{ShardsID: shardID}
+	var deleted struct {
+		SoundID Snowflake `json:"sound_id"`
+		GuildID Snowflake `json:"guild_id"`
+	}
+	if err := unmarshalEventData(data, &deleted); err != nil {
+		h.logger.Error("guildSoundboardSoundDeleteHandlers: Failed parsing event data")
+		return
+	}
+
+	if sound, ok := cache.GetSoundboardSound(deleted.GuildID, deleted.SoundID); ok {
+		evt.Sound = sound
+	} else {
+		evt.Sound = SoundBoardSound{SoundID: deleted.SoundID, GuildID: deleted.GuildID}
+	}
+	cache.DelSoundboardSound(deleted.GuildID, deleted.SoundID)
+
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
+
+// addHandler registers a new GUILD_SOUNDBOARD_SOUND_DELETE handler function.
+//
+// This method is not thread-safe.
+func (h *guildSoundboardSoundDeleteHandlers) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(GuildSoundboardSoundDeleteEvent)))
+}
+
+/*****************************
+ * GUILD_SOUNDBOARD_SOUNDS_UPDATE Handler
+ *****************************/
+
+// guildSoundboardSoundsUpdateHandlers manages all registered handlers for GUILD_SOUNDBOARD_SOUNDS_UPDATE events.
+type guildSoundboardSoundsUpdateHandlers struct {
+	logger   Logger
+	handlers []func(GuildSoundboardSoundsUpdateEvent)
+}
+
+// handleEvent parses the GUILD_SOUNDBOARD_SOUNDS_UPDATE event data and calls each registered handler.
+func (h *guildSoundboardSoundsUpdateHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
+	evt := GuildSoundboardSoundsUpdateEvent{ShardsID: shardID}
+	if err := unmarshalEventData(data, &evt); err != nil {
+		h.logger.Error("guildSoundboardSoundsUpdateHandlers: Failed parsing event data")
+		return
+	}
+
+	putGuildSoundboardSounds(cache, evt.GuildID, evt.Sounds)
+
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
+
+// addHandler registers a new GUILD_SOUNDBOARD_SOUNDS_UPDATE handler function.
+//
+// This method is not thread-safe.
+func (h *guildSoundboardSoundsUpdateHandlers) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(GuildSoundboardSoundsUpdateEvent)))
+}
+
+// putGuildSoundboardSounds replaces the cached soundboard sounds of a guild.
+func putGuildSoundboardSounds(cache CacheManager, guildID Snowflake, sounds []SoundBoardSound) {
+	if !cache.Flags().Has(CacheFlagSoundboardSounds) {
+		return
+	}
+	cache.DelGuildSoundboardSounds(guildID)
+	for i := range len(sounds) {
+		sounds[i].GuildID = guildID
+		cache.PutSoundboardSound(sounds[i])
+	}
+}
+
+/*****************************
+ * SOUNDBOARD_SOUNDS Handler
+ *****************************/
+
+// soundboardSoundsRequest collects the answers to a single Request Soundboard Sounds.
+type soundboardSoundsRequest struct {
+	mu      sync.Mutex
+	sounds  map[Snowflake][]SoundBoardSound
+	pending int // number of guilds requested
+	done    chan struct{}
+}
+
+// add stores the sounds of a guild, closing done once every guild answered.
+func (r *soundboardSoundsRequest) add(evt SoundboardSoundsEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sounds[evt.GuildID]; ok {
+		return
+	}
+	r.sounds[evt.GuildID] = evt.Sounds
+	if len(r.sounds) == r.pending {
+		close(r.done)
+	}
+}
+
+// soundboardSoundsHandlers manages all registered handlers for SOUNDBOARD_SOUNDS events,
+// and the pending soundboard sounds requests waiting for their guilds.
+type soundboardSoundsHandlers struct {
+	logger   Logger
+	handlers []func(SoundboardSoundsEvent)
+
+	mu       sync.Mutex
+	requests map[Snowflake][]*soundboardSoundsRequest // guild ID -> pending requests
+}
+
+// handleEvent parses the SOUNDBOARD_SOUNDS event data and calls each registered handler.
+func (h *soundboardSoundsHandlers) handleEvent(cache CacheManager, shardID int, data []byte) {
+	evt := SoundboardSoundsEvent{ShardsID: shardID}
+	if err := unmarshalEventData(data, &evt); err != nil {
+		h.logger.Error("soundboardSoundsHandlers: Failed parsing event data")
+		return
+	}
+
+	putGuildSoundboardSounds(cache, evt.GuildID, evt.Sounds)
+
+	h.mu.Lock()
+	for _, req := range h.requests[evt.GuildID] {
+		req.add(evt)
+	}
+	h.mu.Unlock()
+
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
+
+// addHandler registers a new SOUNDBOARD_SOUNDS handler function.
+//
+// This method is not thread-safe.
+func (h *soundboardSoundsHandlers) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(SoundboardSoundsEvent)))
+}
+
+// track registers a pending soundboard sounds request for the given guilds.
+func (h *soundboardSoundsHandlers) track(guildIDs []Snowflake) *soundboardSoundsRequest {
+	req := &soundboardSoundsRequest{
+		sounds:  make(map[Snowflake][]SoundBoardSound, len(guildIDs)),
+		pending: len(guildIDs),
+		done:    make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.requests == nil {
+		h.requests = make(map[Snowflake][]*soundboardSoundsRequest)
+	}
+	for _, guildID := range guildIDs {
+		h.requests[guildID] = append(h.requests[guildID], req)
+	}
+	return req
+}
+
+// untrack removes a pending soundboard sounds request.
+func (h *soundboardSoundsHandlers) untrack(req *soundboardSoundsRequest, guildIDs []Snowflake) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, guildID := range guildIDs {
+		reqs := slices.DeleteFunc(h.requests[guildID], func(r *soundboardSoundsRequest) bool { return r == req })
+		if len(reqs) == 0 {
+			delete(h.requests, guildID)
+		} else {
+			h.requests[guildID] = reqs
+		}
+	}
+}
+
+/*****************************
+ *   Local Events Handlers
+ *****************************/
+
+// localEventHandlers manages all registered handlers for an event generated by goda itself,
+// instead of being received from the Gateway.
+type localEventHandlers[T any] struct {
+	handlers []func(T)
+}
+
+// handleEvent does nothing, local events are never received from the Gateway.
+func (h *localEventHandlers[T]) handleEvent(cache CacheManager, shardID int, data []byte) {}
+
+// addHandler registers a new handler function.
+//
+// This method is not thread-safe.
+func (h *localEventHandlers[T]) addHandler(handler any) {
+	h.handlers = append(h.handlers, handler.(func(T)))
+}
+
+// emit calls each registered handler with the event.
+func (h *localEventHandlers[T]) emit(evt T) {
+	for _, handler := range h.handlers {
+		handler(evt)
+	}
+}
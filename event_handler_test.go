@@ -0,0 +1,170 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+func TestGuildMembersChunkHandlers_CollectsByNonce(t *testing.T) {
+	cache := NewDefaultCache(CacheFlagMembers)
+	h := &guildMembersChunkHandlers{logger: NewDefaultLogger(io.Discard, LogLevelInfoLevel)}
+	req := h.track("n1", 0)
+	defer h.untrack("n1")
+
+	const chunks = 4
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		// chunks are dispatched concurrently by the worker pool
+		go func() {
+			defer wg.Done()
+			data := `{"guild_id":"1","chunk_index":` + strconv.Itoa(i) + `,"chunk_count":` + strconv.Itoa(chunks) +
+				`,"nonce":"n1","members":[{"user":{"id":"` + strconv.Itoa(100+i) + `"}}]}`
+			h.handleEvent(cache, 0, []byte(data))
+		}()
+	}
+	// a chunk of another request must not be collected
+	h.handleEvent(cache, 0, []byte(`{"guild_id":"1","chunk_count":1,"nonce":"other","members":[{"user":{"id":"200"}}]}`))
+	wg.Wait()
+
+	select {
+	case <-req.done:
+	case <-time.After(time.Second):
+		t.Fatal("request not completed after last chunk")
+	}
+	if len(req.members) != chunks {
+		t.Fatalf("expected %d members got %d", chunks, len(req.members))
+	}
+	for i := range chunks {
+		member, ok := cache.GetMember(1, Snowflake(100+i))
+		if !ok || member.GuildID != 1 {
+			t.Fatalf("member %d not cached with its guild", 100+i)
+		}
+	}
+}
+
+// membersChunk returns the data of the chunk index of count answering the request nonce.
+func membersChunk(nonce string, index, count int) []byte {
+	return []byte(`{"guild_id":"1","chunk_index":` + strconv.Itoa(index) + `,"chunk_count":` + strconv.Itoa(count) +
+		`,"nonce":"` + nonce + `","members":[{"user":{"id":"` + strconv.Itoa(100+index) + `"}}]}`)
+}
+
+func TestGuildMembersChunkHandlers_InvalidChunks(t *testing.T) {
+	cache := NewDefaultCache(CacheFlagsNone)
+	h := &guildMembersChunkHandlers{logger: NewDefaultLogger(io.Discard, LogLevelInfoLevel)}
+
+	for name, chunks := range map[string][][]byte{
+		"index out of range": {membersChunk("n", 0, 2), membersChunk("n", 2, 2)},
+		"repeated index":     {membersChunk("n", 0, 2), membersChunk("n", 0, 2)},
+		"count changed":      {membersChunk("n", 0, 2), membersChunk("n", 1, 3)},
+	} {
+		req := h.track("n", 0)
+		for _, chunk := range chunks {
+			h.handleEvent(cache, 0, chunk)
+		}
+		h.untrack("n")
+
+		select {
+		case <-req.done:
+		default:
+			t.Fatalf("%s: request not completed", name)
+		}
+		if req.err != ErrInvalidMembersChunk {
+			t.Fatalf("%s: expected ErrInvalidMembersChunk got %v", name, req.err)
+		}
+	}
+}
+
+func TestGuildMembersChunkHandlers_LostChunkFailsOnDisconnect(t *testing.T) {
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		io.Copy(io.Discard, conn)
+	})
+	s := newTestShard(url, nil)
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer s.Shutdown()
+
+	h := s.dispatcher.guildMembersChunk()
+	req := h.track("n", s.shardID)
+	defer h.untrack("n")
+	other := h.track("other shard", s.shardID+1)
+	defer h.untrack("other shard")
+
+	// the chunk 1 of 3 is lost with the connection
+	h.handleEvent(s.dispatcher.cacheManager, s.shardID, membersChunk("n", 0, 3))
+	h.handleEvent(s.dispatcher.cacheManager, s.shardID, membersChunk("n", 2, 3))
+	select {
+	case <-req.done:
+		t.Fatal("request completed with a missing chunk")
+	default:
+	}
+
+	s.closeConn()
+	select {
+	case <-req.done:
+	case <-time.After(time.Second):
+		t.Fatal("request not failed when its shard disconnected")
+	}
+	if req.err != ErrMembersRequestInterrupted {
+		t.Fatalf("expected ErrMembersRequestInterrupted got %v", req.err)
+	}
+	select {
+	case <-other.done:
+		t.Fatal("request of another shard failed")
+	default:
+	}
+}
+
+func TestSoundboardSoundsHandlers_CollectsByGuild(t *testing.T) {
+	cache := NewDefaultCache(CacheFlagSoundboardSounds)
+	h := &soundboardSoundsHandlers{logger: NewDefaultLogger(io.Discard, LogLevelInfoLevel)}
+	guildIDs := []Snowflake{1, 2}
+	req := h.track(guildIDs)
+
+	h.handleEvent(cache, 0, []byte(`{"guild_id":"1","soundboard_sounds":[{"id":"10","name":"a"},{"id":"11","name":"b"}]}`))
+	select {
+	case <-req.done:
+		t.Fatal("request completed before every guild answered")
+	default:
+	}
+	h.handleEvent(cache, 0, []byte(`{"guild_id":"2","soundboard_sounds":[]}`))
+
+	select {
+	case <-req.done:
+	case <-time.After(time.Second):
+		t.Fatal("request not completed after last guild")
+	}
+	if len(req.sounds[1]) != 2 || len(req.sounds[2]) != 0 {
+		t.Fatalf("unexpected sounds %+v", req.sounds)
+	}
+	if sound, ok := cache.GetSoundboardSound(1, 11); !ok || sound.Name != "b" || sound.GuildID != 1 {
+		t.Fatalf("sound not cached with its guild: %+v", sound)
+	}
+
+	h.untrack(req, guildIDs)
+	if len(h.requests) != 0 {
+		t.Fatalf("request still tracked: %+v", h.requests)
+	}
+}
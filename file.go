@@ -16,6 +16,7 @@ package goda
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"image"
@@ -24,9 +25,12 @@ import (
 	_ "image/png"
 	"io"
 	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -109,3 +113,183 @@ func NewImageFile(path string) (Base64Image, error) {
 	encoded := base64.StdEncoding.EncodeToString(data)
 	return fmt.Sprintf("data:%s;base64,%s", mimeType, encoded), nil
 }
+
+/*****************************
+ *       File Uploads
+ *****************************/
+
+// File is a file uploaded as an attachment of a message.
+//
+// Usage example:
+//
+//	f, _ := os.Open("report.log")
+//	defer f.Close()
+//	message, err := client.SendMessage(channelID, goda.MessageCreateOptions{
+//	    Content: "Daily report",
+//	    Files:   []goda.File{{Name: "report.log", Reader: f}},
+//	})
+//
+// Notes:
+//   - Reader is streamed to Discord, it is never buffered whole.
+//   - A request retried after a rate limit or a server error reads the files again,
+//     readers implementing io.Seeker (e.g. *os.File, *bytes.Reader) are rewound,
+//     others fail the request instead.
+//   - The caller closes Reader, if needed.
+type File struct {
+	// Name is the file name, including its extension.
+	Name string
+
+	// Reader provides the content of the file.
+	Reader io.Reader
+
+	// Description is the alt text of the file (max 1024 characters).
+	//
+	// Optional:
+	//  - May be empty string.
+	Description string
+
+	// Spoiler hides the file behind a spoiler.
+	Spoiler bool
+}
+
+// filename returns the name of the file as uploaded.
+func (f File) filename() string {
+	if f.Spoiler && !strings.HasPrefix(f.Name, "SPOILER_") {
+		return "SPOILER_" + f.Name
+	}
+	return f.Name
+}
+
+// fileAttachment is the attachment entry of an uploaded file in the JSON payload,
+// its ID is the index of the file in the upload.
+type fileAttachment struct {
+	ID          int    `json:"id"`
+	Filename    string `json:"filename"`
+	Description string `json:"description,omitempty"`
+}
+
+// withFileAttachments adds the attachment entries of files to the JSON object payload,
+// after the attachments it already lists.
+func withFileAttachments(payload []byte, files []File) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+
+	var attachments []json.RawMessage
+	if raw, ok := fields["attachments"]; ok {
+		if err := json.Unmarshal(raw, &attachments); err != nil {
+			return nil, err
+		}
+	}
+	for i, f := range files {
+		entry, _ := json.Marshal(fileAttachment{ID: i, Filename: f.filename(), Description: f.Description})
+		attachments = append(attachments, entry)
+	}
+
+	fields["attachments"], _ = json.Marshal(attachments)
+	return json.Marshal(fields)
+}
+
+// marshalUpload encodes v as the JSON payload of an upload of files.
+func marshalUpload(v any, files []File) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil || len(files) == 0 {
+		return payload, err
+	}
+	return withFileAttachments(payload, files)
+}
+
+// fileUpload is a multipart/form-data request body, holding the JSON payload and the files.
+type fileUpload struct {
+	payload []byte
+	files   []File
+	offsets []int64        // start offsets of seekable readers, -1 for others
+	pr      *io.PipeReader // body of the last attempt, nil before the first one
+	done    chan struct{}  // closed once the last attempt stopped reading the files
+}
+
+// newFileUpload creates the upload of files along with the JSON payload.
+func newFileUpload(payload []byte, files []File) *fileUpload {
+	u := &fileUpload{payload: payload, files: files, offsets: make([]int64, len(files))}
+	for i, f := range files {
+		u.offsets[i] = -1
+		if seeker, ok := f.Reader.(io.Seeker); ok {
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				u.offsets[i] = offset
+			}
+		}
+	}
+	return u
+}
+
+// body returns a reader streaming the multipart body and its content type.
+//
+// Every call after the first stops the previous attempt and rewinds the files,
+// failing if a file cannot be rewound.
+func (u *fileUpload) body() (io.ReadCloser, string, error) {
+	if u.done != nil {
+		for i, f := range u.files {
+			if u.offsets[i] < 0 {
+				return nil, "", errors.New("goda: cannot resend file " + f.Name + ", its reader is not an io.Seeker")
+			}
+		}
+
+		// the previous attempt may still be copying a file, wait until it
+		// stops reading the files before rewinding them
+		u.pr.Close()
+		<-u.done
+
+		for i, f := range u.files {
+			if _, err := f.Reader.(io.Seeker).Seek(u.offsets[i], io.SeekStart); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	done := make(chan struct{})
+	u.pr, u.done = pr, done
+	go func() {
+		defer close(done)
+		pw.CloseWithError(u.write(mw))
+	}()
+	return pr, mw.FormDataContentType(), nil
+}
+
+// quoteEscaper escapes the file names of Content-Disposition headers.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// write writes the payload_json part and a files[i] part per file to mw.
+func (u *fileUpload) write(mw *multipart.Writer) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(u.payload); err != nil {
+		return err
+	}
+
+	for i, f := range u.files {
+		contentType := mime.TypeByExtension(filepath.Ext(f.Name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="files[`+strconv.Itoa(i)+`]"; filename="`+
+			quoteEscaper.Replace(f.filename())+`"`)
+		header.Set("Content-Type", contentType)
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f.Reader); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
@@ -14,13 +14,18 @@
 package goda
 
 import (
+	"context"
 	"encoding/base64"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // small valid PNG image (1x1 transparent pixel) (thanks to ChatGPT for that)
@@ -94,3 +99,141 @@ func TestNewImageFile_NonImage(t *testing.T) {
 		t.Error("expected error for non-image file, got nil")
 	}
 }
+
+// blockingFile is a seekable file reader whose first Read blocks until release is closed,
+// recording if it is rewound while a Read is in progress.
+type blockingFile struct {
+	r            *strings.Reader
+	reading      chan struct{} // closed once the first Read started
+	release      chan struct{} // unblocks the first Read
+	once         sync.Once
+	inRead       atomic.Bool
+	seekedInRead atomic.Bool
+}
+
+func newBlockingFile(data string) *blockingFile {
+	return &blockingFile{r: strings.NewReader(data), reading: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (f *blockingFile) Read(p []byte) (int, error) {
+	f.inRead.Store(true)
+	defer f.inRead.Store(false)
+	f.once.Do(func() {
+		close(f.reading)
+		<-f.release
+	})
+	return f.r.Read(p)
+}
+
+func (f *blockingFile) Seek(offset int64, whence int) (int64, error) {
+	if f.inRead.Load() {
+		f.seekedInRead.Store(true)
+	}
+	return f.r.Seek(offset, whence)
+}
+
+// newUploadServer starts a REST API rate limiting the first request,
+// and reporting the parts of the multipart requests it receives.
+func newUploadServer(t *testing.T, parts chan<- map[string]string) *restApi {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("expected multipart request: %v", err)
+			return
+		}
+		received := make(map[string]string)
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(part)
+			received[part.FormName()+":"+part.FileName()] = string(data)
+		}
+		parts <- received
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	logger := NewDefaultLogger(io.Discard, LogLevelFatalLevel)
+	req := newRequester(nil, "token", logger)
+	req.baseURL = srv.URL
+	return newRestApi(req, logger)
+}
+
+func TestSendMessage_UploadsFiles(t *testing.T) {
+	parts := make(chan map[string]string, 1)
+	api := newUploadServer(t, parts)
+
+	_, err := api.SendMessage(123, MessageCreateOptions{
+		Content: "report",
+		Files: []File{
+			{Name: "chart.png", Reader: strings.NewReader("png data"), Spoiler: true},
+			{Name: "run.log", Reader: strings.NewReader("log data"), Description: "logs"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	received := <-parts
+	if received["files[0]:SPOILER_chart.png"] != "png data" || received["files[1]:run.log"] != "log data" {
+		t.Fatalf("files not rewound and uploaded after the rate limit: %v", received)
+	}
+	payload := received["payload_json:"]
+	if !strings.Contains(payload, `"content":"report"`) ||
+		!strings.Contains(payload, `{"id":0,"filename":"SPOILER_chart.png"}`) ||
+		!strings.Contains(payload, `{"id":1,"filename":"run.log","description":"logs"}`) {
+		t.Fatalf("unexpected payload_json %s", payload)
+	}
+}
+
+func TestSendMessage_UnseekableFileNotResent(t *testing.T) {
+	api := newUploadServer(t, make(chan map[string]string, 1))
+
+	_, err := api.SendMessage(123, MessageCreateOptions{
+		Files: []File{{Name: "stream.txt", Reader: io.MultiReader(strings.NewReader("data"))}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot resend file stream.txt") {
+		t.Fatalf("expected resend error, got %v", err)
+	}
+}
+
+func TestRequester_Do_RetryWaitsForPreviousUpload(t *testing.T) {
+	file := newBlockingFile("file data")
+	var attempts atomic.Int32
+	bodies := make(chan string, 1)
+	r := newTestRequester(func(req *http.Request) (*http.Response, error) {
+		if attempts.Add(1) == 1 {
+			// rate limit the first attempt while its file is being read
+			go io.Copy(io.Discard, req.Body)
+			<-file.reading
+			time.AfterFunc(50*time.Millisecond, func() { close(file.release) })
+			return newMockResponse(429, `{"message":"rate limited","retry_after":0.01}`, map[string]string{"Retry-After": "0.01"}), nil
+		}
+		body, _ := io.ReadAll(req.Body)
+		bodies <- string(body)
+		return newMockResponse(200, `{"id":"1"}`, nil), nil
+	})
+
+	resp, err := r.do(context.Background(), "POST", "/channels/123/messages", []byte(`{}`),
+		[]File{{Name: "data.txt", Reader: file}}, true, "")
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+
+	if file.seekedInRead.Load() {
+		t.Fatal("file rewound while the previous attempt was reading it")
+	}
+	if body := <-bodies; !strings.Contains(body, "\r\n\r\nfile data\r\n") {
+		t.Fatalf("file not fully resent after the rate limit: %q", body)
+	}
+}
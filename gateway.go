@@ -121,6 +121,65 @@ const (
 	GatewayIntentDirectMessagePolls GatewayIntent = 1 << 25
 )
 
+// GatewayEncoding represents the encoding of the payloads exchanged on Gateway connections.
+//
+// Reference: https://discord.com/developers/docs/topics/gateway#encoding-and-compression
+type GatewayEncoding string
+
+const (
+	// GatewayEncodingJSON encodes payloads as JSON text frames.
+	GatewayEncodingJSON GatewayEncoding = "json"
+
+	// GatewayEncodingETF encodes payloads as Erlang External Term Format binary frames.
+	//
+	// ETF payloads are smaller than JSON ones and are decoded directly into the events.
+	GatewayEncodingETF GatewayEncoding = "etf"
+)
+
+// GatewayCompression represents the transport compression used on Gateway connections.
+//
+// Transport compression compresses the whole WebSocket stream of a shard,
+// which greatly reduces bandwidth on large bots (e.g. GUILD_CREATE bursts).
+type GatewayCompression string
+
+const (
+	// GatewayCompressionNone disables transport compression.
+	GatewayCompressionNone GatewayCompression = ""
+
+	// GatewayCompressionZlibStream enables zlib-stream transport compression.
+	//
+	// Each shard keeps a single inflate context for its connection and
+	// decodes complete payloads before dispatching them.
+	GatewayCompressionZlibStream GatewayCompression = "zlib-stream"
+
+	// GatewayCompressionZstdStream enables zstd-stream transport compression.
+	//
+	// Each shard keeps a single zstd context for its connection and
+	// decodes every payload as soon as it is received.
+	GatewayCompressionZstdStream GatewayCompression = "zstd-stream"
+)
+
+// GatewayDecompressor decompresses the transport-compressed stream of a single
+// Gateway connection.
+//
+// A new decompressor is created for every connection since each connection
+// starts a new compressed stream. Implementations do not need to be thread-safe,
+// a decompressor is only used by the read loop of its shard.
+type GatewayDecompressor interface {
+	// Decompress feeds a binary WebSocket message into the stream.
+	//
+	// It returns the complete decompressed payload, or nil if more
+	// messages are needed to complete it. The returned slice may be
+	// reused by the next call to Decompress.
+	Decompress(data []byte) ([]byte, error)
+
+	// Close releases the resources held by the decompressor.
+	Close() error
+}
+
+// GatewayDecompressorFactory creates a new GatewayDecompressor for a connection.
+type GatewayDecompressorFactory func() GatewayDecompressor
+
 // gatewayOpcode represents the operation codes used in Discord Gateway WebSocket frames.
 //
 // Each opcode defines a specific action or message type in the client-server communication.
@@ -204,7 +263,7 @@ const (
 //
 // Fields:
 //   - op: Operation code indicating the type of payload (e.g., Dispatch, Heartbeat).
-//   - d: Raw JSON-encoded event data or payload data.
+//   - d: Raw JSON-encoded event data or payload data, or an ETF term with the ETF encoding.
 //   - s: Sequence number of the event; only provided when 'op' is Dispatch (0).
 //   - t: Event name; only provided when 'op' is Dispatch (0).
 type gatewayPayload struct {
@@ -214,6 +273,42 @@ type gatewayPayload struct {
 	T  string          `json:"t"`  // Event name; present only if op == 0 (Dispatch).
 }
 
+var _ etfUnmarshaler = (*gatewayPayload)(nil)
+
+// unmarshalETF decodes the payload, keeping its data as an ETF term
+// decoded once its event is known.
+func (p *gatewayPayload) unmarshalETF(d *etfDecoder) error {
+	n, err := d.mapHeader()
+	if err != nil {
+		return err
+	}
+	for range n {
+		key, err := d.text()
+		if err != nil {
+			return err
+		}
+		switch string(key) {
+		case "op":
+			err = d.unmarshal(&p.Op)
+		case "s":
+			err = d.unmarshal(&p.S)
+		case "t":
+			err = d.unmarshal(&p.T)
+		case "d":
+			start := d.pos
+			if err = d.skip(); err == nil {
+				p.D = append([]byte{etfVersion}, d.src[start:d.pos]...)
+			}
+		default:
+			err = d.skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GatewayCloseEventCode represents Discord Gateway close event codes.
 type GatewayCloseEventCode int
 
@@ -303,6 +398,33 @@ const (
 	GatewayCloseEventCodeDisallowedIntents GatewayCloseEventCode = 4014
 )
 
+// Is checks if the close event code matches the provided code.
+func (c GatewayCloseEventCode) Is(code GatewayCloseEventCode) bool {
+	return c == code
+}
+
+// Reconnectable returns false if reconnecting after this close code is useless,
+// e.g. invalid token or disallowed intents.
+func (c GatewayCloseEventCode) Reconnectable() bool {
+	switch c {
+	case GatewayCloseEventCodeAuthenticationFailed,
+		GatewayCloseEventCodeInvalidShard,
+		GatewayCloseEventCodeShardingRequired,
+		GatewayCloseEventCodeInvalidAPIVersion,
+		GatewayCloseEventCodeInvalidIntents,
+		GatewayCloseEventCodeDisallowedIntents:
+		return false
+	default:
+		return true
+	}
+}
+
+// ResetsSession returns true if this close code invalidates the session,
+// so the shard must identify again instead of resuming.
+func (c GatewayCloseEventCode) ResetsSession() bool {
+	return c == GatewayCloseEventCodeInvalidSeq || c == GatewayCloseEventCodeSessionTimedOut
+}
+
 // gateway holds the Discord Gateway URL.
 type gateway struct {
 	// WSS URL that can be used for connecting to the Gateway
@@ -316,10 +438,19 @@ type GatewayBot struct {
 	// Recommended number of shards to use when connecting
 	Shards int `json:"shards"`
 	// Information on the current session start limit
-	SessionStartLimit struct {
-		Total          int `json:"total"`
-		Remaining      int `json:"remaining"`
-		ResetAfter     int `json:"reset_after"`
-		MaxConcurrency int `json:"max_concurrency"`
-	} `json:"session_start_limit"`
+	SessionStartLimit SessionStartLimit `json:"session_start_limit"`
+}
+
+// SessionStartLimit is the number of sessions a bot may start (Identify payloads it may send).
+//
+// Reference: https://discord.com/developers/docs/events/gateway#session-start-limit-object
+type SessionStartLimit struct {
+	// Total number of session starts the current user is allowed
+	Total int `json:"total"`
+	// Remaining number of session starts the current user is allowed
+	Remaining int `json:"remaining"`
+	// Number of milliseconds after which the limit resets
+	ResetAfter int `json:"reset_after"`
+	// Number of identify requests allowed per 5 seconds
+	MaxConcurrency int `json:"max_concurrency"`
 }
@@ -0,0 +1,156 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Benchmark harness for Gateway transport decompressors.
+//
+// The harness replays recorded Gateway payloads through every codec in
+// gatewayCodecs and reports CPU time, throughput and allocations per codec:
+//
+//	go test -run '^$' -bench GatewayDecompressor -benchmem
+//
+// By default a synthetic recording is used. Point GODA_GATEWAY_RECORDING
+// to a file holding one raw Gateway payload per line to replay real traffic.
+// Go has no zstd encoder, so zstd-stream replays messages compressed by the
+// reference library with testdata/gen_zstd_stream.go: testdata/zstd_stream.bin
+// for the synthetic recording, or the file GODA_GATEWAY_RECORDING_ZSTD points to.
+// To compare another codec, add an entry with its encoder and decompressor
+// factory to gatewayCodecs.
+
+// gatewayCodec describes a transport compression under benchmark.
+type gatewayCodec struct {
+	name      string
+	compress  func(tb testing.TB, payloads [][]byte) [][]byte
+	newReader GatewayDecompressorFactory
+}
+
+var gatewayCodecs = []gatewayCodec{
+	{
+		name:      "none",
+		compress:  func(_ testing.TB, payloads [][]byte) [][]byte { return payloads },
+		newReader: func() GatewayDecompressor { return identityDecompressor{} },
+	},
+	{
+		name:      string(GatewayCompressionZlibStream),
+		compress:  zlibStreamFrames,
+		newReader: newZlibStreamDecompressor,
+	},
+	{
+		name:      string(GatewayCompressionZstdStream),
+		compress:  zstdStreamFrames,
+		newReader: newZstdStreamDecompressor,
+	},
+}
+
+// identityDecompressor is the uncompressed baseline.
+type identityDecompressor struct{}
+
+func (identityDecompressor) Decompress(data []byte) ([]byte, error) { return data, nil }
+func (identityDecompressor) Close() error                           { return nil }
+
+// loadGatewayRecording returns the payloads to replay.
+func loadGatewayRecording(tb testing.TB) [][]byte {
+	path := os.Getenv("GODA_GATEWAY_RECORDING")
+	if path == "" {
+		return syntheticGatewayRecording()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		tb.Fatalf("open recording: %v", err)
+	}
+	defer f.Close()
+
+	var payloads [][]byte
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 1<<20), 64<<20)
+	for sc.Scan() {
+		if line := bytes.TrimSpace(sc.Bytes()); len(line) > 0 {
+			payloads = append(payloads, bytes.Clone(line))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		tb.Fatalf("read recording: %v", err)
+	}
+	return payloads
+}
+
+// syntheticGatewayRecording mimics a startup burst followed by chat traffic.
+func syntheticGatewayRecording() [][]byte {
+	payloads := [][]byte{[]byte(`{"op":10,"d":{"heartbeat_interval":41250}}`)}
+	for g := range 20 {
+		guildID := strconv.Itoa(1000000000000000000 + g)
+		var members strings.Builder
+		for m := range 100 {
+			if m > 0 {
+				members.WriteByte(',')
+			}
+			members.WriteString(`{"user":{"id":"` + strconv.Itoa(2000000000000000000+m) +
+				`","username":"member` + strconv.Itoa(m) + `","discriminator":"0"},"roles":[],"joined_at":"2024-01-01T00:00:00.000000+00:00","deaf":false,"mute":false}`)
+		}
+		payloads = append(payloads, []byte(`{"op":0,"s":`+strconv.Itoa(g+1)+`,"t":"GUILD_CREATE","d":{"id":"`+
+			guildID+`","name":"guild `+strconv.Itoa(g)+`","member_count":100,"members":[`+members.String()+`]}}`))
+	}
+	for i := range 200 {
+		payloads = append(payloads, []byte(`{"op":0,"s":`+strconv.Itoa(i+21)+
+			`,"t":"MESSAGE_CREATE","d":{"id":"`+strconv.Itoa(3000000000000000000+i)+
+			`","channel_id":"1100000000000000000","author":{"id":"2000000000000000001","username":"member1"},"content":"message number `+
+			strconv.Itoa(i)+`","timestamp":"2024-01-01T00:00:00.000000+00:00","tts":false,"mention_everyone":false,"mentions":[],"attachments":[],"embeds":[]}}`))
+	}
+	return payloads
+}
+
+func BenchmarkGatewayDecompressor(b *testing.B) {
+	payloads := loadGatewayRecording(b)
+
+	var rawSize int64
+	for _, p := range payloads {
+		rawSize += int64(len(p))
+	}
+
+	for _, codec := range gatewayCodecs {
+		b.Run(codec.name, func(b *testing.B) {
+			frames := codec.compress(b, payloads)
+			b.ReportAllocs()
+			b.SetBytes(rawSize)
+			b.ResetTimer()
+
+			var wireSize int64
+			for _, f := range frames {
+				wireSize += int64(len(f))
+			}
+			b.ReportMetric(float64(wireSize)/float64(rawSize), "wire-ratio")
+
+			for range b.N {
+				// each iteration replays the recording on a fresh connection
+				d := codec.newReader()
+				for _, f := range frames {
+					if _, err := d.Decompress(f); err != nil {
+						b.Fatal(err)
+					}
+				}
+				d.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+//go:build ignore
+
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+// gen_error_codes generates error_codes.go from the JSON error codes table of
+// the Discord API documentation, found in docs/topics/Opcodes_and_Status_Codes.md
+// of https://github.com/discord/discord-api-docs.
+//
+// Without argument, as run by go generate, the page is downloaded from the main
+// branch of the documentation repository:
+//
+//	go generate ./...
+//
+// A local checkout of the documentation can be used instead:
+//
+//	go run gen_error_codes.go path/to/discord-api-docs/docs/topics/Opcodes_and_Status_Codes.md
+//
+// Codes already in error_codes.go keep their constant name. New codes are named
+// after their message and printed, review their names before committing. Codes
+// no longer documented are removed and printed too.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const output = "error_codes.go"
+
+// docsURL is the documentation page read when no path is given.
+const docsURL = "https://raw.githubusercontent.com/discord/discord-api-docs/main/docs/topics/Opcodes_and_Status_Codes.md"
+
+type errorCode struct {
+	name    string
+	code    int
+	message string
+}
+
+func main() {
+	if len(os.Args) > 2 {
+		log.Fatal("usage: go run gen_error_codes.go [Opcodes_and_Status_Codes.md]")
+	}
+	source := docsURL
+	if len(os.Args) == 2 {
+		source = os.Args[1]
+	}
+
+	docs, err := openDocs(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	codes, err := readDocsTable(source, docs)
+	docs.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	names, err := readConstNames(output)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	documented := make(map[int]bool, len(codes))
+	for i := range codes {
+		documented[codes[i].code] = true
+		if name, ok := names[codes[i].code]; ok {
+			codes[i].name = name
+			continue
+		}
+		codes[i].name = "JSONErrorCode" + constName(codes[i].message)
+		fmt.Printf("new code %d: %s\n", codes[i].code, codes[i].name)
+	}
+	for code, name := range names {
+		if !documented[code] {
+			fmt.Printf("removed code %d: %s\n", code, name)
+		}
+	}
+
+	src, err := format.Source(generate(codes))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// openDocs opens the documentation page at source, downloading it if source is docsURL.
+func openDocs(source string) (io.ReadCloser, error) {
+	if source != docsURL {
+		return os.Open(source)
+	}
+
+	resp, err := http.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", source, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// readDocsTable returns the rows of the table following the "JSON Error Codes" heading
+// of the documentation page read from r.
+func readDocsTable(source string, r io.Reader) ([]errorCode, error) {
+	var codes []errorCode
+	inSection := false
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "#") {
+			if inSection && len(codes) > 0 {
+				break
+			}
+			inSection = strings.Contains(strings.ToLower(line), "json error codes")
+			continue
+		}
+		if !inSection || !strings.HasPrefix(line, "|") {
+			continue
+		}
+
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		if len(cells) < 2 {
+			continue
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(cells[0]))
+		if err != nil {
+			continue // header and separator rows
+		}
+		message := cleanMarkdown(strings.Join(cells[1:], "|"))
+		codes = append(codes, errorCode{code: code, message: message})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("%s: no JSON error codes table found", source)
+	}
+	return codes, nil
+}
+
+var reMarkdownLink = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// cleanMarkdown returns the plain text of a table cell.
+func cleanMarkdown(s string) string {
+	s = reMarkdownLink.ReplaceAllString(s, "$1")
+	s = strings.NewReplacer("`", "", `\_`, "_", `\*`, "*").Replace(s)
+	return strings.TrimSuffix(strings.TrimSpace(s), ".")
+}
+
+// readConstNames returns the names of the JSONErrorCode constants of path, by code.
+func readConstNames(path string) (map[int]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[int]string{}, nil
+		}
+		return nil, err
+	}
+
+	names := map[int]string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != "JSONErrorCode" || len(value.Values) != 1 {
+				continue
+			}
+			lit, ok := value.Values[0].(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			code, err := strconv.Atoi(lit.Value)
+			if err != nil {
+				return nil, err
+			}
+			names[code] = value.Names[0].Name
+		}
+	}
+	return names, nil
+}
+
+// constName returns the words of message in CamelCase, without punctuation.
+func constName(message string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(message, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	return b.String()
+}
+
+func generate(codes []errorCode) []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	b.WriteString("const (\n")
+	for i, c := range codes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "\t// %s is the \"%s\" error.\n\t%s JSONErrorCode = %d\n", c.name, c.message, c.name, c.code)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// jsonErrorCodeMessages holds the documented message of every JSON error code.\n")
+	b.WriteString("var jsonErrorCodeMessages = map[JSONErrorCode]string{\n")
+	for _, c := range codes {
+		fmt.Fprintf(&b, "\t%s: %s,\n", c.name, strconv.Quote(c.message))
+	}
+	b.WriteString("}\n")
+	b.WriteString(footer)
+	return b.Bytes()
+}
+
+const header = `/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+// Code generated by gen_error_codes.go from the Discord API documentation. DO NOT EDIT.
+
+package goda
+
+import "strconv"
+
+// JSONErrorCode is a Discord JSON error code, returned in the "code" field
+// of the body of failing REST API responses.
+//
+// Every code is also an error matching the DiscordAPIError carrying it through errors.Is.
+//
+// Usage example:
+//
+//	err := client.DeleteMessage(channelID, messageID, "")
+//	if errors.Is(err, goda.JSONErrorCodeUnknownMessage) {
+//	    // the message was already deleted
+//	}
+//
+// Reference: https://discord.com/developers/docs/topics/opcodes-and-status-codes#json-json-error-codes
+//
+//go:generate go run gen_error_codes.go
+type JSONErrorCode int
+
+`
+
+const footer = `
+// String returns the documented message of the code, or "Unknown JSON error code N".
+func (c JSONErrorCode) String() string {
+	if msg, ok := jsonErrorCodeMessages[c]; ok {
+		return msg
+	}
+	return "Unknown JSON error code " + strconv.Itoa(int(c))
+}
+
+// Error implements the error interface, so codes can be used as errors.Is targets.
+func (c JSONErrorCode) Error() string {
+	return "goda: discord json error code " + strconv.Itoa(int(c)) + ": " + c.String()
+}
+`
@@ -2,10 +2,13 @@ module github.com/ra7eemi/goda
 
 go 1.22
 
-require github.com/gobwas/ws v1.4.0
+require (
+	github.com/gobwas/ws v1.4.0
+	golang.org/x/crypto v0.33.0
+)
 
 require (
 	github.com/gobwas/httphead v0.1.0 // indirect
 	github.com/gobwas/pool v0.2.1 // indirect
-	golang.org/x/sys v0.6.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
 )
@@ -680,15 +680,7 @@ func (g *GatewayGuild) UnmarshalJSON(buf []byte) error {
 	g.StageInstances = temp.StageInstances
 	g.SoundboardSounds = temp.SoundboardSounds
 
-	for i := range len(g.Roles) {
-		g.Roles[i].GuildID = g.ID
-	}
-	for i := range len(g.Members) {
-		g.Members[i].GuildID = g.ID
-	}
-	for i := range len(g.VoiceStates) {
-		g.VoiceStates[i].GuildID = g.ID
-	}
+	g.setGuildIDs()
 
 	if temp.Channels != nil {
 		g.Channels = make([]GuildChannel, 0, len(temp.Channels))
@@ -711,6 +703,30 @@ func (g *GatewayGuild) UnmarshalJSON(buf []byte) error {
 	return nil
 }
 
+var _ etfUnmarshaler = (*GatewayGuild)(nil)
+
+func (g *GatewayGuild) unmarshalETF(d *etfDecoder) error {
+	type noMethod GatewayGuild
+	if err := d.unmarshal((*noMethod)(g)); err != nil {
+		return err
+	}
+	g.setGuildIDs()
+	return nil
+}
+
+// setGuildIDs sets the guild ID the Gateway omits on the roles, members and voice states of the guild.
+func (g *GatewayGuild) setGuildIDs() {
+	for i := range len(g.Roles) {
+		g.Roles[i].GuildID = g.ID
+	}
+	for i := range len(g.Members) {
+		g.Members[i].GuildID = g.ID
+	}
+	for i := range len(g.VoiceStates) {
+		g.VoiceStates[i].GuildID = g.ID
+	}
+}
+
 // PartialGuild represents a partial struct of a Discord guild.
 //
 // Reference: https://discord.com/developers/docs/resources/guild
@@ -424,45 +424,64 @@ var (
 
 // Helper func to Unmarshal any interaction type to a Interaction interface.
 func UnmarshalInteraction(buf []byte) (Interaction, error) {
-	var meta struct {
-		Type InteractionType `json:"type"`
-		Data struct {
-			Type ApplicationCommandType `json:"type"`
-		} `json:"data"`
-	}
+	var meta interactionMeta
 	if err := json.Unmarshal(buf, &meta); err != nil {
 		return nil, err
 	}
 
+	i, err := meta.interaction()
+	if err != nil {
+		return nil, err
+	}
+	return i, json.Unmarshal(buf, i)
+}
+
+// unmarshalInteractionETF is the ETF counterpart of UnmarshalInteraction.
+func unmarshalInteractionETF(d *etfDecoder) (Interaction, error) {
+	var meta interactionMeta
+	if err := d.peek(&meta); err != nil {
+		return nil, err
+	}
+
+	i, err := meta.interaction()
+	if err != nil {
+		return nil, err
+	}
+	return i, d.unmarshal(i)
+}
+
+// interactionMeta holds the fields selecting the type of an interaction.
+type interactionMeta struct {
+	Type InteractionType `json:"type"`
+	Data struct {
+		Type ApplicationCommandType `json:"type"`
+	} `json:"data"`
+}
+
+// interaction returns a new interaction of the type described by meta.
+func (meta interactionMeta) interaction() (Interaction, error) {
 	switch meta.Type {
 	case InteractionTypePing:
-		var i PingInteraction
-		return &i, json.Unmarshal(buf, &i)
+		return &PingInteraction{}, nil
 
 	case InteractionTypeApplicationCommand:
 		switch meta.Data.Type {
 		case ApplicationCommandTypeChatInput:
-			var i ChatInputCommandInteraction
-			return &i, json.Unmarshal(buf, &i)
+			return &ChatInputCommandInteraction{}, nil
 		case ApplicationCommandTypeUser:
-			var i UserCommandInteraction
-			return &i, json.Unmarshal(buf, &i)
+			return &UserCommandInteraction{}, nil
 		case ApplicationCommandTypeMessage:
-			var i MessageCommandInteraction
-			return &i, json.Unmarshal(buf, &i)
+			return &MessageCommandInteraction{}, nil
 		default:
 			return nil, errors.New("unknown application interacton type")
 		}
 
 	case InteractionTypeComponent:
-		var i ComponentInteraction
-		return &i, json.Unmarshal(buf, &i)
+		return &ComponentInteraction{}, nil
 	case InteractionTypeAutocomplete:
-		var i AutoCompleteInteraction
-		return &i, json.Unmarshal(buf, &i)
+		return &AutoCompleteInteraction{}, nil
 	case InteractionTypeModalSubmit:
-		var i ModalSubmitInteraction
-		return &i, json.Unmarshal(buf, &i)
+		return &ModalSubmitInteraction{}, nil
 	default:
 		return nil, errors.New("unknown interaction type")
 	}
@@ -506,6 +506,17 @@ func (n *Nonce) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+var _ etfUnmarshaler = (*Nonce)(nil)
+
+func (n *Nonce) unmarshalETF(d *etfDecoder) error {
+	nonce, err := d.textOrInt()
+	if err != nil {
+		return err
+	}
+	*n = Nonce(nonce)
+	return nil
+}
+
 // MessageCall represents a call associated with a message.
 //
 // Reference: https://discord.com/developers/docs/resources/message#message-call-object
@@ -1063,6 +1074,9 @@ type MessageCreateOptions struct {
 	Flags            MessageFlags       `json:"flags,omitempty"`
 	EnforceNonce     bool               `json:"enforce_nonce,omitempty"`
 	Poll             *PollCreateOptions `json:"poll,omitempty"`
+
+	// Files are uploaded as attachments of the message.
+	Files []File `json:"-"`
 }
 
 /////////////
@@ -1194,14 +1208,15 @@ func (b *EmbedBuilder) Build() Embed {
  *   Message Action Methods  *
  *****************************/
 
-// Reply sends a reply to this message.
+// Reply sends a reply to this message, with files as attachments.
 // Returns the new message that was sent.
 //
 // Usage example:
 //
 //	reply, err := message.Reply("Hello!")
-func (m *Message) Reply(content string) (*Message, error) {
-	return m.ReplyWith(MessageCreateOptions{Content: content})
+//	reply, err := message.Reply("Here is the chart", goda.File{Name: "chart.png", Reader: bytes.NewReader(png)})
+func (m *Message) Reply(content string, files ...File) (*Message, error) {
+	return m.ReplyWith(MessageCreateOptions{Content: content, Files: files})
 }
 
 // ReplyWith sends a reply with full message options.
@@ -464,6 +464,18 @@ func (p *Permissions) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+var _ etfUnmarshaler = (*Permissions)(nil)
+
+// unmarshalETF decodes permissions sent as an integer or a decimal binary.
+func (p *Permissions) unmarshalETF(d *etfDecoder) error {
+	perms, err := d.uintOrText()
+	if err != nil {
+		return err
+	}
+	*p = Permissions(perms)
+	return nil
+}
+
 // Method used internally by the library.
 func (p Permissions) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + strconv.FormatUint(uint64(p), 10) + `"`), nil
@@ -0,0 +1,151 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+// StatusType represents the online status of a user.
+//
+// Reference: https://discord.com/developers/docs/events/gateway-events#update-presence-status-types
+type StatusType string
+
+const (
+	// StatusTypeOnline shows the user as online.
+	StatusTypeOnline StatusType = "online"
+	// StatusTypeDoNotDisturb shows the user as do not disturb.
+	StatusTypeDoNotDisturb StatusType = "dnd"
+	// StatusTypeIdle shows the user as AFK.
+	StatusTypeIdle StatusType = "idle"
+	// StatusTypeInvisible shows the user as offline while staying connected.
+	StatusTypeInvisible StatusType = "invisible"
+	// StatusTypeOffline shows the user as offline.
+	StatusTypeOffline StatusType = "offline"
+)
+
+// Is checks if the status type matches the provided type.
+func (t StatusType) Is(statusType StatusType) bool {
+	return t == statusType
+}
+
+// ActivityType represents the type of an activity.
+//
+// Reference: https://discord.com/developers/docs/events/gateway-events#activity-object-activity-types
+type ActivityType int
+
+const (
+	// ActivityTypePlaying displays as "Playing {Name}".
+	ActivityTypePlaying ActivityType = iota
+	// ActivityTypeStreaming displays as "Streaming {Name}", requires a Twitch or YouTube URL.
+	ActivityTypeStreaming
+	// ActivityTypeListening displays as "Listening to {Name}".
+	ActivityTypeListening
+	// ActivityTypeWatching displays as "Watching {Name}".
+	ActivityTypeWatching
+	// ActivityTypeCustom displays as "{Emoji} {State}".
+	ActivityTypeCustom
+	// ActivityTypeCompeting displays as "Competing in {Name}".
+	ActivityTypeCompeting
+)
+
+// Is checks if the activity type matches the provided type.
+func (t ActivityType) Is(activityType ActivityType) bool {
+	return t == activityType
+}
+
+// Activity represents an activity shown in a bot's presence.
+//
+// Reference: https://discord.com/developers/docs/events/gateway-events#activity-object
+type Activity struct {
+	// Name is the activity's name.
+	Name string `json:"name"`
+
+	// Type is the activity's type.
+	Type ActivityType `json:"type"`
+
+	// URL is the stream URL.
+	//
+	// Optional:
+	//   - Only used when Type is ActivityTypeStreaming.
+	URL string `json:"url,omitempty"`
+
+	// State is the user's current party status, or the text of a custom status.
+	//
+	// Optional:
+	//   - May be empty.
+	State string `json:"state,omitempty"`
+}
+
+// gatewayPresence is the data of a presence update, sent with opcode 3
+// or as part of the Identify payload.
+//
+// Reference: https://discord.com/developers/docs/events/gateway-events#update-presence
+type gatewayPresence struct {
+	Since      *int64     `json:"since"`
+	Activities []Activity `json:"activities"`
+	Status     StatusType `json:"status"`
+	AFK        bool       `json:"afk"`
+}
+
+// newGatewayPresence builds the presence payload for the given status and activities.
+func newGatewayPresence(status StatusType, activities []Activity) *gatewayPresence {
+	if status == "" {
+		status = StatusTypeOnline
+	}
+	if activities == nil {
+		// Discord requires an array, even if empty
+		activities = []Activity{}
+	}
+	return &gatewayPresence{Activities: activities, Status: status}
+}
+
+// ClientStatus represents the status of a user on each of their active platforms.
+//
+// Reference: https://discord.com/developers/docs/events/gateway-events#client-status-object
+type ClientStatus struct {
+	// Desktop is the user's status on a desktop application session.
+	//
+	// Optional:
+	//   - Will be empty if the user has no desktop session.
+	Desktop StatusType `json:"desktop,omitempty"`
+
+	// Mobile is the user's status on a mobile application session.
+	//
+	// Optional:
+	//   - Will be empty if the user has no mobile session.
+	Mobile StatusType `json:"mobile,omitempty"`
+
+	// Web is the user's status on a web browser or bot session.
+	//
+	// Optional:
+	//   - Will be empty if the user has no web session.
+	Web StatusType `json:"web,omitempty"`
+}
+
+// Presence represents the presence of a user in a guild.
+//
+// Reference: https://discord.com/developers/docs/events/gateway-events#presence-update
+type Presence struct {
+	// User is the user the presence is for, only its ID is guaranteed.
+	User User `json:"user"`
+
+	// GuildID is the ID of the guild the presence is for.
+	GuildID Snowflake `json:"guild_id"`
+
+	// Status is the user's overall status.
+	Status StatusType `json:"status"`
+
+	// Activities is the user's current activities.
+	Activities []Activity `json:"activities"`
+
+	// ClientStatus is the user's status per platform.
+	ClientStatus ClientStatus `json:"client_status"`
+}
@@ -0,0 +1,320 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*****************************
+ *       Rate Limiter
+ *****************************/
+
+// RateLimiter keeps the REST requests of the client within the Discord rate limits.
+//
+// Requests are grouped by bucket key (the route with its major parameter, e.g. "GET:/channels/123/messages").
+// Before sending a request the client calls Wait, then calls Update exactly once
+// with the headers of the response, or nil headers if no response was received.
+//
+// Implementations must be safe for concurrent use.
+type RateLimiter interface {
+	// Wait blocks until a request of the bucket may be sent.
+	//
+	// Returns the context error if ctx is done first, in which case Update is not called.
+	Wait(ctx context.Context, bucketKey string) error
+
+	// Update records the rate limit headers of the response to a request of the bucket.
+	//
+	// headers is nil if the request failed before a response was received.
+	Update(bucketKey string, headers http.Header)
+}
+
+// parseRetryAfter returns the Retry-After delay of a 429 response, 1 second if it has none.
+func parseRetryAfter(h http.Header) time.Duration {
+	if sec, err := strconv.ParseFloat(h.Get(headerRetryAfter), 64); err == nil {
+		whole, frac := math.Modf(sec)
+		return time.Duration(whole)*time.Second + time.Duration(frac*1000)*time.Millisecond
+	}
+	return time.Second
+}
+
+// parseBucketHeaders returns the remaining requests of the bucket and the time it resets,
+// ok is false if the headers have no bucket state.
+func parseBucketHeaders(h http.Header) (remaining int, reset time.Time, ok bool) {
+	remaining, err := strconv.Atoi(h.Get(headerRemaining))
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	resetAfter, err := strconv.ParseFloat(h.Get(headerResetAfter), 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return remaining, time.Now().Add(time.Duration(resetAfter * float64(time.Second))), true
+}
+
+// parseBucketReset returns the time the bucket resets as reported by Discord, ok is false if the headers have none.
+//
+// Unlike the Reset-After delay, it is the same for every response of a rate limit window.
+func parseBucketReset(h http.Header) (reset time.Time, ok bool) {
+	sec, err := strconv.ParseFloat(h.Get(headerReset), 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(math.Round(sec * 1000))), true
+}
+
+// isGlobalRateLimit reports whether the headers of a 429 response limit every route.
+func isGlobalRateLimit(h http.Header) bool {
+	return h.Get(headerGlobal) == "true" || h.Get(headerScope) == "shared"
+}
+
+/*****************************
+ *   Memory Rate Limiter
+ *****************************/
+
+// globalRateLimit stores the earliest time global requests can resume.
+type globalRateLimit int64
+
+// set updates the global reset time if the new time is later.
+func (g *globalRateLimit) set(t time.Time) {
+	newVal := t.UnixNano()
+	for {
+		oldVal := atomic.LoadInt64((*int64)(g))
+		if newVal <= oldVal {
+			return
+		}
+		if atomic.CompareAndSwapInt64((*int64)(g), oldVal, newVal) {
+			return
+		}
+	}
+}
+
+// get returns the current global reset time.
+func (g *globalRateLimit) get() time.Time {
+	return time.Unix(0, atomic.LoadInt64((*int64)(g)))
+}
+
+// ratelimitBucket holds per-route rate limit info.
+//
+// lock is held from Wait to Update, so the requests of a bucket are sent one at a time.
+type ratelimitBucket struct {
+	lock      chan struct{}
+	remaining int
+	resetAt   time.Time
+}
+
+// MemoryRateLimiter is a RateLimiter tracking the buckets within a single process.
+//
+// It is the default rate limiter of the client.
+type MemoryRateLimiter struct {
+	buckets sync.Map // bucket key -> *ratelimitBucket
+	global  globalRateLimit
+}
+
+var _ RateLimiter = (*MemoryRateLimiter)(nil)
+
+// NewMemoryRateLimiter creates a new in-process RateLimiter.
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{}
+}
+
+// bucket returns the bucket of key, creating it if needed.
+func (rl *MemoryRateLimiter) bucket(key string) *ratelimitBucket {
+	if b, ok := rl.buckets.Load(key); ok {
+		return b.(*ratelimitBucket)
+	}
+	b, _ := rl.buckets.LoadOrStore(key, &ratelimitBucket{lock: make(chan struct{}, 1), remaining: 1})
+	return b.(*ratelimitBucket)
+}
+
+// Wait implements RateLimiter.
+//
+// It takes the bucket until Update is called, then waits for the bucket reset
+// if it has no request left, and for the end of the global rate limit.
+func (rl *MemoryRateLimiter) Wait(ctx context.Context, bucketKey string) error {
+	b := rl.bucket(bucketKey)
+	select {
+	case b.lock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if b.remaining == 0 && time.Now().Before(b.resetAt) {
+		if err := sleepContext(ctx, time.Until(b.resetAt)+50*time.Millisecond); err != nil {
+			<-b.lock
+			return err
+		}
+	}
+
+	if now, globalReset := time.Now(), rl.global.get(); globalReset.After(now) {
+		if err := sleepContext(ctx, globalReset.Sub(now)+100*time.Millisecond); err != nil {
+			<-b.lock
+			return err
+		}
+	}
+	return nil
+}
+
+// Update implements RateLimiter.
+func (rl *MemoryRateLimiter) Update(bucketKey string, headers http.Header) {
+	b := rl.bucket(bucketKey)
+
+	if remaining, reset, ok := parseBucketHeaders(headers); ok {
+		b.remaining = remaining
+		b.resetAt = reset
+	}
+	if isGlobalRateLimit(headers) {
+		rl.global.set(time.Now().Add(parseRetryAfter(headers)))
+	}
+
+	select {
+	case <-b.lock:
+	default:
+	}
+}
+
+/*****************************
+ *   Shared Rate Limiter
+ *****************************/
+
+// RateLimitStore holds the rate limit state shared by the processes of a SharedRateLimiter,
+// e.g. in Redis or a database.
+//
+// Every process using the same bot token must use the same store,
+// and a store must not be shared by different tokens.
+//
+// Implementations must be safe for concurrent use, and Take must be atomic across processes.
+type RateLimitStore interface {
+	// Take reserves a request of key.
+	//
+	// If key has a request left, Take consumes it and returns 0.
+	// If key has none left, Take returns the time left until it resets.
+	// If key was never set or its reset time passed, Take returns 0,
+	// and with a positive hold, sets key to no request left for hold.
+	Take(ctx context.Context, key string, hold time.Duration) (time.Duration, error)
+
+	// Set records that key has remaining requests left until reset.
+	//
+	// Responses of a window may arrive out of order, so if key is already set to the same
+	// reset time, which has not passed, Set keeps the lower of the stored and given remaining.
+	// Otherwise Set replaces the state of key, including a hold set by Take.
+	Set(ctx context.Context, key string, remaining int, reset time.Time) error
+}
+
+const (
+	// sharedRateLimitGlobalKey is the store key of the global rate limit.
+	sharedRateLimitGlobalKey = "global"
+	// sharedRateLimitHold is how long the first request of an unknown bucket holds it,
+	// until its response reports the bucket state. It matches the REST client timeout.
+	sharedRateLimitHold = 30 * time.Second
+	// sharedRateLimitPoll is the longest delay between attempts to take a request of a bucket.
+	sharedRateLimitPoll = 100 * time.Millisecond
+)
+
+// SharedRateLimiter is a RateLimiter sharing the buckets between the processes
+// running the same bot token through a RateLimitStore.
+//
+// The first request of an unknown bucket is sent alone,
+// then requests are sent concurrently while the bucket reported by Discord has some left.
+//
+// Notes:
+//   - Processes must have synchronized clocks, as the state holds wall clock times.
+//   - If the store fails, the error is logged and the request is sent;
+//     the client still retries the requests Discord rate limits.
+type SharedRateLimiter struct {
+	store  RateLimitStore
+	logger Logger
+}
+
+var _ RateLimiter = (*SharedRateLimiter)(nil)
+
+// NewSharedRateLimiter creates a RateLimiter using store.
+// Store errors are logged to logger, which may be nil.
+//
+// Usage example:
+//
+//	limiter := goda.NewSharedRateLimiter(myRedisStore, nil)
+//	client := goda.New(ctx, goda.WithRateLimiter(limiter))
+func NewSharedRateLimiter(store RateLimitStore, logger Logger) *SharedRateLimiter {
+	return &SharedRateLimiter{store: store, logger: logger}
+}
+
+// Wait implements RateLimiter.
+//
+// It waits for the end of the global rate limit, then takes a request of the bucket from the store.
+func (rl *SharedRateLimiter) Wait(ctx context.Context, bucketKey string) error {
+	if err := rl.take(ctx, sharedRateLimitGlobalKey, 0); err != nil {
+		return err
+	}
+	return rl.take(ctx, bucketKey, sharedRateLimitHold)
+}
+
+// take polls the store until a request of key is taken.
+func (rl *SharedRateLimiter) take(ctx context.Context, key string, hold time.Duration) error {
+	for {
+		wait, err := rl.store.Take(ctx, key, hold)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			rl.logError("Rate limit store failed taking "+key, err)
+			return nil
+		}
+		if wait <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, min(wait, sharedRateLimitPoll)); err != nil {
+			return err
+		}
+	}
+}
+
+// Update implements RateLimiter.
+//
+// The reset time of the bucket is taken from the X-RateLimit-Reset header when present,
+// so the responses of a window report the same reset time to the store.
+// Responses without bucket headers release the hold of the bucket, if any.
+func (rl *SharedRateLimiter) Update(bucketKey string, headers http.Header) {
+	ctx := context.Background()
+
+	remaining, reset, ok := parseBucketHeaders(headers)
+	if !ok {
+		remaining, reset = 1, time.Now()
+	} else if windowReset, ok := parseBucketReset(headers); ok {
+		reset = windowReset
+	}
+	if err := rl.store.Set(ctx, bucketKey, remaining, reset); err != nil {
+		rl.logError("Rate limit store failed updating "+bucketKey, err)
+	}
+
+	if isGlobalRateLimit(headers) {
+		reset := time.Now().Add(parseRetryAfter(headers))
+		if err := rl.store.Set(ctx, sharedRateLimitGlobalKey, 0, reset); err != nil {
+			rl.logError("Rate limit store failed updating "+sharedRateLimitGlobalKey, err)
+		}
+	}
+}
+
+// logError logs a store error if the rate limiter has a logger.
+func (rl *SharedRateLimiter) logError(msg string, err error) {
+	if rl.logger != nil {
+		rl.logger.WithField("err", err).Error(msg)
+	}
+}
@@ -0,0 +1,221 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// standInRateLimitStore is a RateLimitStore standing in for a store shared by processes, such as Redis.
+type standInRateLimitStore struct {
+	mu    sync.Mutex
+	state map[string]standInBucket
+}
+
+type standInBucket struct {
+	remaining int
+	reset     time.Time
+}
+
+func newStandInRateLimitStore() *standInRateLimitStore {
+	return &standInRateLimitStore{state: make(map[string]standInBucket)}
+}
+
+func (s *standInRateLimitStore) Take(ctx context.Context, key string, hold time.Duration) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	b, ok := s.state[key]
+	if !ok || !now.Before(b.reset) {
+		if hold > 0 {
+			s.state[key] = standInBucket{remaining: 0, reset: now.Add(hold)}
+		}
+		return 0, nil
+	}
+	if b.remaining > 0 {
+		b.remaining--
+		s.state[key] = b
+		return 0, nil
+	}
+	return b.reset.Sub(now), nil
+}
+
+func (s *standInRateLimitStore) Set(ctx context.Context, key string, remaining int, reset time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.state[key]; ok && b.reset.Equal(reset) && time.Now().Before(reset) {
+		remaining = min(remaining, b.remaining)
+	}
+	s.state[key] = standInBucket{remaining: remaining, reset: reset}
+	return nil
+}
+
+// wait returns the time left until key resets if it has no request left.
+func (s *standInRateLimitStore) wait(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.state[key]; ok && b.remaining == 0 {
+		return max(time.Until(b.reset), 0)
+	}
+	return 0
+}
+
+func TestSharedRateLimiter_CoordinatesProcesses(t *testing.T) {
+	const (
+		limit  = 5
+		window = 500 * time.Millisecond
+	)
+
+	// the mock Discord allows limit requests per window on the route,
+	// and answers the earlier requests of a window last
+	var mu sync.Mutex
+	var windowEnd time.Time
+	var used int
+	var rateLimited, total atomic.Int32
+	discord := func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		now := time.Now()
+		if !now.Before(windowEnd) {
+			windowEnd, used = now.Add(window).Truncate(time.Millisecond), 0
+		}
+		resetAfter := fmt.Sprintf("%.3f", windowEnd.Sub(now).Seconds())
+		reset := fmt.Sprintf("%.3f", float64(windowEnd.UnixMilli())/1000)
+		if used == limit {
+			mu.Unlock()
+			rateLimited.Add(1)
+			return newMockResponse(429, `{"message":"You are being rate limited."}`, map[string]string{
+				"Retry-After":             resetAfter,
+				"X-RateLimit-Remaining":   "0",
+				"X-RateLimit-Reset":       reset,
+				"X-RateLimit-Reset-After": resetAfter,
+			}), nil
+		}
+		used++
+		remaining := limit - used
+		mu.Unlock()
+
+		total.Add(1)
+		time.Sleep(time.Duration(remaining) * 5 * time.Millisecond)
+		return newMockResponse(200, `{"ok":true}`, map[string]string{
+			"X-RateLimit-Remaining":   fmt.Sprint(remaining),
+			"X-RateLimit-Reset":       reset,
+			"X-RateLimit-Reset-After": resetAfter,
+		}), nil
+	}
+
+	// two requesters sharing the store act as two processes of the bot
+	store := newStandInRateLimitStore()
+	processes := []*requester{newTestRequester(discord), newTestRequester(discord)}
+	for _, r := range processes {
+		r.limiter = NewSharedRateLimiter(store, nil)
+	}
+
+	const requestsPerProcess = 5
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, r := range processes {
+		for range requestsPerProcess {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
+				if err != nil {
+					t.Errorf("request error: %v", err)
+					return
+				}
+				resp.Body.Close()
+			}()
+		}
+	}
+	wg.Wait()
+
+	if n := rateLimited.Load(); n != 0 {
+		t.Errorf("expected processes to share the bucket, got %d rate limited requests", n)
+	}
+	if n := total.Load(); n != 2*requestsPerProcess {
+		t.Fatalf("expected %d requests, got %d", 2*requestsPerProcess, n)
+	}
+	minExpected := (2*requestsPerProcess/limit - 1) * window
+	if elapsed := time.Since(start); elapsed < minExpected {
+		t.Fatalf("expected at least %v for %d requests, got %v", minExpected, total.Load(), elapsed)
+	}
+}
+
+func TestSharedRateLimiter_SharesGlobalRateLimit(t *testing.T) {
+	var first atomic.Bool
+	discord := func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/users/@me") && first.CompareAndSwap(false, true) {
+			return newMockResponse(429, `{"message":"global rate limit","global":true}`, map[string]string{
+				"Retry-After":        "0.3",
+				"X-RateLimit-Global": "true",
+			}), nil
+		}
+		return newMockResponse(200, `{"ok":true}`, nil), nil
+	}
+
+	store := newStandInRateLimitStore()
+	a, b := newTestRequester(discord), newTestRequester(discord)
+	a.limiter = NewSharedRateLimiter(store, nil)
+	b.limiter = NewSharedRateLimiter(store, nil)
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		resp, err := a.do(context.Background(), "GET", "/users/@me", nil, nil, true, "")
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+
+	// wait for the global rate limit to be shared, then request another route from the other process
+	for store.wait(sharedRateLimitGlobalKey) == 0 {
+		if time.Since(start) > time.Second {
+			t.Fatal("global rate limit not recorded in the store")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	resp, err := b.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("expected the other process to wait for the global rate limit, waited %v", elapsed)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_WithRateLimiter(t *testing.T) {
+	limiter := NewSharedRateLimiter(newStandInRateLimitStore(), nil)
+	c := New(context.Background(), WithRateLimiter(limiter))
+	defer c.Shutdown()
+
+	if c.restApi.req.limiter != limiter {
+		t.Fatal("expected the requester to use the configured rate limiter")
+	}
+	if _, ok := newRequester(nil, "token", nil).limiter.(*MemoryRateLimiter); !ok {
+		t.Fatal("expected a MemoryRateLimiter by default")
+	}
+}
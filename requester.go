@@ -15,15 +15,13 @@ package goda
 
 import (
 	"bytes"
-	"errors"
+	"context"
 	"fmt"
-	"math"
+	"io"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
-	"sync/atomic"
 	"time"
 )
 
@@ -32,6 +30,7 @@ import (
  ***********************/
 
 const (
+	// apiVersion is not configurable, as goda's types model the payloads of this version.
 	apiVersion       = "v10"
 	baseApiUrl       = "https://discord.com/api/" + apiVersion
 	maxRetries       = 5
@@ -39,49 +38,13 @@ const (
 	headerRetryAfter = "Retry-After"
 	headerGlobal     = "X-RateLimit-Global"
 	headerRemaining  = "X-RateLimit-Remaining"
+	headerReset      = "X-RateLimit-Reset"
 	headerResetAfter = "X-RateLimit-Reset-After"
 	headerBucket     = "X-RateLimit-Bucket"
 	headerScope      = "X-RateLimit-Scope"
 	headerReason     = "X-Audit-Log-Reason"
 )
 
-/***********************
- *   GlobalRateLimit   *
- ***********************/
-
-// globalRateLimit stores the earliest time global requests can resume.
-type globalRateLimit int64
-
-// set updates the global reset time if the new time is later.
-func (g *globalRateLimit) set(t time.Time) {
-	newVal := t.UnixNano()
-	for {
-		oldVal := atomic.LoadInt64((*int64)(g))
-		if newVal <= oldVal {
-			return
-		}
-		if atomic.CompareAndSwapInt64((*int64)(g), oldVal, newVal) {
-			return
-		}
-	}
-}
-
-// get returns the current global reset time.
-func (g *globalRateLimit) get() time.Time {
-	return time.Unix(0, atomic.LoadInt64((*int64)(g)))
-}
-
-/***********************
- *   ratelimitBucket   *
- ***********************/
-
-// ratelimitBucket holds per-route rate limit info but no methods.
-type ratelimitBucket struct {
-	sync.Mutex
-	remaining int
-	resetAt   time.Time
-}
-
 /***********************
  *   Requester         *
  ***********************/
@@ -89,9 +52,9 @@ type ratelimitBucket struct {
 // requester handles HTTP requests with Discord rate limit compliance.
 type requester struct {
 	client               *http.Client
+	baseURL              string // REST API base URL, including the API version
 	token                string
-	buckets              sync.Map // map[bucketRoute:majorParam]*Bucket
-	global               globalRateLimit
+	limiter              RateLimiter // keeps requests within the rate limits of their bucket
 	userAgent            string
 	logger               Logger
 	retryableStatusCodes map[int]struct{}
@@ -100,27 +63,13 @@ type requester struct {
 // newRequester creates a new Requester with the given bot token and logger.
 func newRequester(client *http.Client, token string, logger Logger) *requester {
 	if client == nil {
-		client = &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-
-				MaxIdleConns:        500,
-				MaxIdleConnsPerHost: 100,
-				MaxConnsPerHost:     200,
-
-				IdleConnTimeout:       120 * time.Second,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-
-				DisableKeepAlives: false,
-				ForceAttemptHTTP2: true,
-			},
-		}
+		client = transportConfig{}.httpClient()
 	}
 
 	return &requester{
 		client:    client,
+		baseURL:   baseApiUrl,
+		limiter:   NewMemoryRateLimiter(),
 		token:     "Bot " + token,
 		userAgent: "DiscordBot (goda)",
 		logger:    logger,
@@ -143,55 +92,47 @@ func (r *requester) Shutdown() {
 	}
 }
 
-// updateBucket updates bucket state from headers.
-func (r *requester) updateBucket(b *ratelimitBucket, h http.Header) {
-	if rem := h.Get(headerRemaining); rem != "" {
-		if n, err := strconv.Atoi(rem); err == nil {
-			b.remaining = n
-		}
-	}
-	if resetAfter := h.Get(headerResetAfter); resetAfter != "" {
-		if dur, err := strconv.ParseFloat(resetAfter, 64); err == nil {
-			b.resetAt = time.Now().Add(time.Duration(dur * float64(time.Second)))
-		}
-	}
-}
-
 // do sends an HTTP request with automatic rate limit and retry handling.
-func (r *requester) do(method, url string, body []byte, authenticateWithToken bool, reason string) (*http.Response, error) {
+//
+// ctx cancels the in-flight request and aborts rate limit and retry waits,
+// returning the context error.
+//
+// With files, the request is a multipart/form-data upload with body as payload_json.
+func (r *requester) do(ctx context.Context, method, url string, body []byte, files []File, authenticateWithToken bool, reason string) (*http.Response, error) {
 	bucketKey := r.generateBucketKey(method, url)
 
-	// Get or create bucket for rate limit tracking
-	bucket, _ := r.buckets.LoadOrStore(bucketKey, &ratelimitBucket{remaining: 1})
-	b := bucket.(*ratelimitBucket)
+	var upload *fileUpload
+	if len(files) > 0 {
+		upload = newFileUpload(body, files)
+	}
 
+	// lastErr is the failure of the last attempt, wrapped in ErrMaxRetries once they are exhausted
+	var lastErr error
 	for tries := range maxRetries {
 		r.logger.Debug(fmt.Sprintf("Attempt #%d %s %s", tries+1, method, url))
 
-		b.Lock()
-
-		if b.remaining == 0 && time.Now().Before(b.resetAt) {
-			wait := time.Until(b.resetAt) + 50*time.Millisecond
-			r.logger.Debug(
-				fmt.Sprintf("Bucket rate limited on route %s: waiting %v before retrying", bucketKey, wait),
-			)
-			b.Unlock()
-			time.Sleep(wait)
-			b.Lock()
+		if err := r.limiter.Wait(ctx, bucketKey); err != nil {
+			return nil, err
 		}
 
-		if now, globalReset := time.Now(), r.global.get(); globalReset.After(now) {
-			wait := globalReset.Sub(now) + 100*time.Millisecond
-			r.logger.Debug(
-				fmt.Sprintf("Global rate limit active: waiting %v before retrying request %s %s", wait, method, url),
-			)
-			b.Unlock()
-			time.Sleep(wait)
-			b.Lock()
+		var reqBody io.Reader = bytes.NewReader(body)
+		contentType := "application/json"
+		if upload != nil {
+			stream, streamType, err := upload.body()
+			if err != nil {
+				r.limiter.Update(bucketKey, nil)
+				r.logger.Error(fmt.Sprintf("Failed building request for %s %s: %v", method, url, err))
+				return nil, err
+			}
+			reqBody, contentType = stream, streamType
 		}
 
-		req, err := http.NewRequest(method, baseApiUrl+url, bytes.NewReader(body))
+		req, err := http.NewRequestWithContext(ctx, method, r.baseURL+url, reqBody)
 		if err != nil {
+			if closer, ok := reqBody.(io.Closer); ok {
+				closer.Close()
+			}
+			r.limiter.Update(bucketKey, nil)
 			r.logger.Error(fmt.Sprintf("Failed building request for %s %s: %v", method, url, err))
 			return nil, err
 		}
@@ -201,7 +142,7 @@ func (r *requester) do(method, url string, body []byte, authenticateWithToken bo
 		}
 		req.Header.Set("User-Agent", r.userAgent)
 		if method == "POST" || method == "PUT" || method == "PATCH" {
-			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Content-Type", contentType)
 		}
 		req.Header.Set("Accept", "application/json")
 
@@ -212,51 +153,54 @@ func (r *requester) do(method, url string, body []byte, authenticateWithToken bo
 		// Execute request
 		resp, err := r.client.Do(req)
 		if err != nil {
+			r.limiter.Update(bucketKey, nil)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			r.logger.Warn(fmt.Sprintf("HTTP request error for %s %s: %v", method, url, err))
-			b.Unlock()
-			time.Sleep(time.Second)
+			lastErr = err
+			if err := sleepContext(ctx, time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		// Handle rate limits and retryable errors
 		if resp.StatusCode == 429 {
-			retry := resp.Header.Get(headerRetryAfter)
-			retryAfter := time.Second
-			if retry != "" {
-				if sec, err := strconv.ParseFloat(retry, 64); err == nil {
-					whole, frac := math.Modf(sec)
-					retryAfter = time.Duration(whole)*time.Second + time.Duration(frac*1000)*time.Millisecond
-				}
-			}
-
+			retryAfter := parseRetryAfter(resp.Header)
 			r.logger.Debug(fmt.Sprintf("429 rate limit hit on route %s, retrying after %v", bucketKey, retryAfter))
 
-			r.updateBucket(b, resp.Header)
-			if resp.Header.Get(headerGlobal) == "true" || resp.Header.Get(headerScope) == "shared" {
-				r.global.set(time.Now().Add(retryAfter))
+			r.limiter.Update(bucketKey, resp.Header)
+			lastErr = readAPIError(resp)
+			if err := sleepContext(ctx, retryAfter); err != nil {
+				return nil, err
 			}
-
-			b.Unlock()
-			resp.Body.Close()
-			time.Sleep(retryAfter)
 			continue
 		}
 
 		if _, retry := r.retryableStatusCodes[resp.StatusCode]; retry {
 			r.logger.Warn(fmt.Sprintf("Retryable status %d for %s %s, retrying...", resp.StatusCode, method, url))
-			resp.Body.Close()
-			b.Unlock()
-			time.Sleep(time.Second)
+			r.limiter.Update(bucketKey, resp.Header)
+			lastErr = readAPIError(resp)
+			if err := sleepContext(ctx, time.Second); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
-		r.updateBucket(b, resp.Header)
-		b.Unlock()
+		r.limiter.Update(bucketKey, resp.Header)
 		return resp, nil
 	}
 
 	r.logger.Error(fmt.Sprintf("Max retries reached for %s %s", method, url))
-	return nil, errors.New("max request retries reached")
+	return nil, fmt.Errorf("%w: %w", ErrMaxRetries, lastErr)
+}
+
+// readAPIError reads and closes the body of a failed response, decoded as a DiscordAPIError.
+func readAPIError(resp *http.Response) *DiscordAPIError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return newDiscordAPIError(resp.StatusCode, body)
 }
 
 var (
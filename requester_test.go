@@ -14,6 +14,8 @@
 package goda
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -61,7 +63,7 @@ func TestRequester_Do_Success(t *testing.T) {
 		}), nil
 	})
 
-	resp, err := r.do("GET", "/channels/123/messages", nil, true, "")
+	resp, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -87,7 +89,7 @@ func TestRequester_Do_RateLimitRetry(t *testing.T) {
 		}), nil
 	})
 
-	resp, err := r.do("GET", "/channels/123/messages", nil, true, "")
+	resp, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -114,7 +116,7 @@ func TestRequester_Do_GlobalRateLimit(t *testing.T) {
 		return newMockResponse(200, `{"ok":true}`, nil), nil
 	})
 
-	resp, err := r.do("GET", "/channels/123/messages", nil, true, "")
+	resp, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -134,7 +136,7 @@ func TestRequester_Do_RetryableStatusCodes(t *testing.T) {
 		return newMockResponse(200, `{"ok":true}`, nil), nil
 	})
 
-	resp, err := r.do("GET", "/channels/123/messages", nil, true, "")
+	resp, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,10 +154,49 @@ func TestRequester_Do_MaxRetriesExceeded(t *testing.T) {
 		return newMockResponse(503, "Service Unavailable", nil), nil
 	})
 
-	_, err := r.do("GET", "/channels/123/messages", nil, true, "")
+	_, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
 	if err == nil || !strings.Contains(err.Error(), "max retries") {
 		t.Fatalf("expected max retries error, got %v", err)
 	}
+	var apiErr *DiscordAPIError
+	if !errors.Is(err, ErrMaxRetries) || !errors.As(err, &apiErr) || apiErr.HTTPStatus != 503 {
+		t.Fatalf("expected ErrMaxRetries wrapping the last 503, got %v", err)
+	}
+}
+
+func TestRequester_Do_ContextCancelsWaits(t *testing.T) {
+	r := newTestRequester(func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(429, `{"message":"rate limited"}`, map[string]string{
+			"Retry-After":             "60",
+			"X-RateLimit-Remaining":   "0",
+			"X-RateLimit-Reset-After": "60",
+		}), nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := r.do(ctx, "POST", "/interactions/123/token/callback", nil, nil, false, "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("retry wait not aborted, took %v", elapsed)
+	}
+}
+
+func TestRequester_Do_ContextCancelsInFlight(t *testing.T) {
+	r := newTestRequester(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := r.do(ctx, "GET", "/channels/123/messages", nil, nil, true, "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
 }
 
 func TestRequester_ConcurrencyStress(t *testing.T) {
@@ -176,7 +217,7 @@ func TestRequester_ConcurrencyStress(t *testing.T) {
 		func() {
 			defer wg.Done()
 			for range requestsPerGoroutine {
-				resp, err := r.do("GET", "/channels/123/messages", nil, true, "")
+				resp, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
 				if err != nil {
 					t.Errorf("request error: %v", err)
 					return
@@ -244,7 +285,7 @@ func TestRequester_ConcurrentRateLimitEnforcement(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for range requestsPerGoroutine {
-				resp, err := r.do("GET", "/channels/123/messages", nil, true, "")
+				resp, err := r.do(context.Background(), "GET", "/channels/123/messages", nil, nil, true, "")
 				if err != nil {
 					t.Errorf("request error: %v", err)
 					return
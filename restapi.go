@@ -14,10 +14,9 @@
 package goda
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"io"
-	"net/http"
 	"net/url"
 	"strconv"
 	"time"
@@ -31,6 +30,7 @@ import (
 type restApi struct {
 	req    *requester
 	logger Logger
+	ctx    context.Context // context of the requests, nil uses context.Background
 }
 
 // newRestApi creates a new RestAPI instance with optional custom requester and logger.
@@ -49,27 +49,66 @@ func (r *restApi) Shutdown() {
 	r.req = nil
 }
 
+// WithContext returns a copy of the REST API client whose requests use ctx.
+//
+// Cancelling ctx, or reaching its deadline, cancels in-flight requests
+// and aborts rate limit and retry waits with the context error.
+//
+// Usage example:
+//
+//	// interactions must be answered within 3 seconds
+//	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+//	defer cancel()
+//	err := client.WithContext(ctx).CreateInteractionResponse(interactionID, token, response)
+//
+// Notes:
+//   - The copy shares the rate limits and connections of the client.
+//   - Without it, requests are only bounded by the HTTP client timeout (30 seconds)
+//     and retries, which may block for a minute or more.
+func (r *restApi) WithContext(ctx context.Context) *restApi {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// requestContext returns the context of the requests.
+func (r *restApi) requestContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 func (r *restApi) doRequest(method, endpoint string, body []byte, authWithToken bool, reason string) ([]byte, error) {
+	return r.doUpload(method, endpoint, body, nil, authWithToken, reason)
+}
+
+// doUpload is doRequest uploading files along with the JSON body, if any.
+func (r *restApi) doUpload(method, endpoint string, body []byte, files []File, authWithToken bool, reason string) ([]byte, error) {
 	r.logger.Debug("Calling endpoint: " + method + endpoint)
 
-	res, err := r.req.do(method, endpoint, body, authWithToken, reason)
+	res, err := r.req.do(r.requestContext(), method, endpoint, body, files, authWithToken, reason)
 	if err != nil {
 		r.logger.Error("Request failed for endpoint " + method + endpoint + ": " + err.Error())
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode == http.StatusUnauthorized {
-		r.logger.Error("Request failed for endpoint " + method + endpoint + ": Invalid Token")
-		return nil, errors.New("invalid token")
-	}
-
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
 		r.logger.Error("Failed reading response body for endpoint " + method + endpoint + ": " + err.Error())
 		return nil, err
 	}
 
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		apiErr := newDiscordAPIError(res.StatusCode, bodyBytes)
+		r.logger.Error("Request failed for endpoint " + method + endpoint + ": " + apiErr.Error())
+		return nil, apiErr
+	}
+
 	r.logger.Debug("Successfully called endpoint: " + method + endpoint)
 	return bodyBytes, nil
 }
@@ -830,11 +869,12 @@ func (r *restApi) TriggerTypingIndicator(channelID Snowflake) error {
 //   - Message: the message object.
 //   - error: if the request or decoding failed.
 func (r *restApi) SendMessage(channelID Snowflake, opts MessageCreateOptions) (Message, error) {
-	reqBody, err := json.Marshal(opts)
-	body, err := r.doRequest("POST", "/channels/"+channelID.String()+"/messages", reqBody, true, "")
-
 	var message Message
-
+	reqBody, err := marshalUpload(opts, opts.Files)
+	if err != nil {
+		return message, err
+	}
+	body, err := r.doUpload("POST", "/channels/"+channelID.String()+"/messages", reqBody, opts.Files, true, "")
 	if err != nil {
 		return message, err
 	}
@@ -934,6 +974,11 @@ type MessageEditOptions struct {
 	Components []LayoutComponent `json:"components,omitempty"`
 	// Attachments are the attachments to keep or add.
 	Attachments []Attachment `json:"attachments,omitempty"`
+	// Files are uploaded as new attachments of the message.
+	//
+	// Note:
+	//   - Existing attachments not listed in Attachments are removed when uploading files.
+	Files []File `json:"-"`
 }
 
 // EditMessage edits a previously sent message.
@@ -944,8 +989,11 @@ type MessageEditOptions struct {
 //	    Content: "Updated content",
 //	})
 func (r *restApi) EditMessage(channelID, messageID Snowflake, opts MessageEditOptions) (Message, error) {
-	reqBody, _ := json.Marshal(opts)
-	body, err := r.doRequest("PATCH", "/channels/"+channelID.String()+"/messages/"+messageID.String(), reqBody, true, "")
+	reqBody, err := marshalUpload(opts, opts.Files)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := r.doUpload("PATCH", "/channels/"+channelID.String()+"/messages/"+messageID.String(), reqBody, opts.Files, true, "")
 	if err != nil {
 		return Message{}, err
 	}
@@ -1746,6 +1794,8 @@ type InteractionResponseData struct {
 	CustomID string `json:"custom_id,omitempty"`
 	// Title is the title for a modal (max 45 characters).
 	Title string `json:"title,omitempty"`
+	// Files are uploaded as attachments of the message.
+	Files []File `json:"-"`
 }
 
 // InteractionResponse is the response structure for an interaction.
@@ -1769,8 +1819,20 @@ type InteractionResponse struct {
 //	})
 func (r *restApi) CreateInteractionResponse(interactionID Snowflake, token string, response InteractionResponse) error {
 	reqBody, _ := json.Marshal(response)
+	var files []File
+	if response.Data != nil && len(response.Data.Files) > 0 {
+		files = response.Data.Files
+		data, err := marshalUpload(response.Data, files)
+		if err != nil {
+			return err
+		}
+		reqBody, _ = json.Marshal(struct {
+			Type InteractionResponseType `json:"type"`
+			Data json.RawMessage         `json:"data"`
+		}{response.Type, data})
+	}
 	// Note: Interaction responses don't use bot token auth
-	_, err := r.doRequest("POST", "/interactions/"+interactionID.String()+"/"+token+"/callback", reqBody, false, "")
+	_, err := r.doUpload("POST", "/interactions/"+interactionID.String()+"/"+token+"/callback", reqBody, files, false, "")
 	return err
 }
 
@@ -1801,8 +1863,11 @@ func (r *restApi) GetOriginalInteractionResponse(applicationID Snowflake, token
 //	    Content: "Updated content!",
 //	})
 func (r *restApi) EditOriginalInteractionResponse(applicationID Snowflake, token string, data InteractionResponseData) (Message, error) {
-	reqBody, _ := json.Marshal(data)
-	body, err := r.doRequest("PATCH", "/webhooks/"+applicationID.String()+"/"+token+"/messages/@original", reqBody, false, "")
+	reqBody, err := marshalUpload(data, data.Files)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := r.doUpload("PATCH", "/webhooks/"+applicationID.String()+"/"+token+"/messages/@original", reqBody, data.Files, false, "")
 	if err != nil {
 		return Message{}, err
 	}
@@ -1833,8 +1898,11 @@ func (r *restApi) DeleteOriginalInteractionResponse(applicationID Snowflake, tok
 //	    Content: "Followup message!",
 //	})
 func (r *restApi) CreateFollowupMessage(applicationID Snowflake, token string, data InteractionResponseData) (Message, error) {
-	reqBody, _ := json.Marshal(data)
-	body, err := r.doRequest("POST", "/webhooks/"+applicationID.String()+"/"+token, reqBody, false, "")
+	reqBody, err := marshalUpload(data, data.Files)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := r.doUpload("POST", "/webhooks/"+applicationID.String()+"/"+token, reqBody, data.Files, false, "")
 	if err != nil {
 		return Message{}, err
 	}
@@ -1874,8 +1942,11 @@ func (r *restApi) GetFollowupMessage(applicationID Snowflake, token string, mess
 //	    Content: "Edited followup!",
 //	})
 func (r *restApi) EditFollowupMessage(applicationID Snowflake, token string, messageID Snowflake, data InteractionResponseData) (Message, error) {
-	reqBody, _ := json.Marshal(data)
-	body, err := r.doRequest("PATCH", "/webhooks/"+applicationID.String()+"/"+token+"/messages/"+messageID.String(), reqBody, false, "")
+	reqBody, err := marshalUpload(data, data.Files)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := r.doUpload("PATCH", "/webhooks/"+applicationID.String()+"/"+token+"/messages/"+messageID.String(), reqBody, data.Files, false, "")
 	if err != nil {
 		return Message{}, err
 	}
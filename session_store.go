@@ -0,0 +1,291 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+/*****************************
+ *       Session State
+ *****************************/
+
+// SessionState is the state a shard needs to resume its Gateway session.
+type SessionState struct {
+	// ShardID is the ID of the shard owning the session.
+	ShardID int `json:"shard_id"`
+	// TotalShards is the shard count the session was started with.
+	TotalShards int `json:"total_shards"`
+	// SessionID is the ID of the Gateway session.
+	SessionID string `json:"session_id"`
+	// Sequence is the last sequence number received.
+	Sequence int64 `json:"seq"`
+	// ResumeURL is the Gateway URL to resume the session with.
+	ResumeURL string `json:"resume_gateway_url"`
+	// UserID is the ID of the bot user, received in the READY of the session.
+	//
+	// Resumed sessions receive no READY, so the client restores it from here.
+	UserID Snowflake `json:"user_id,omitempty"`
+}
+
+/*****************************
+ *       Session Store
+ *****************************/
+
+// SessionStore persists the session state of shards across process restarts,
+// letting shards resume their sessions instead of identifying again.
+//
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Load returns the stored session state of a shard,
+	// ok is false if no state is stored for it.
+	Load(shardID int) (state SessionState, ok bool, err error)
+	// Save stores the session state of a shard, replacing any previous state.
+	Save(state SessionState) error
+	// Delete removes the stored session state of a shard.
+	Delete(shardID int) error
+}
+
+// BatchSessionStore is a SessionStore also handling the session states of many shards at once.
+//
+// The Client uses it instead of one call per shard when loading and saving sessions,
+// letting stores backed by a file or a database write once for all shards.
+type BatchSessionStore interface {
+	SessionStore
+	// LoadAll returns the stored session states of shardIDs, by shard ID.
+	LoadAll(shardIDs []int) (map[int]SessionState, error)
+	// SaveAll stores the session states of shards, replacing any previous state of them.
+	SaveAll(states []SessionState) error
+	// DeleteAll removes the stored session states of shardIDs.
+	DeleteAll(shardIDs []int) error
+}
+
+// MemorySessionStore is a SessionStore keeping session states in memory.
+//
+// It lets a Client restarted within the same process resume its sessions.
+type MemorySessionStore struct {
+	mu     sync.Mutex
+	states map[int]SessionState
+}
+
+var _ SessionStore = (*MemorySessionStore)(nil)
+
+// NewMemorySessionStore creates a new in-memory SessionStore.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{states: make(map[int]SessionState)}
+}
+
+// Load implements SessionStore.
+func (s *MemorySessionStore) Load(shardID int) (SessionState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[shardID]
+	return state, ok, nil
+}
+
+// Save implements SessionStore.
+func (s *MemorySessionStore) Save(state SessionState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.ShardID] = state
+	return nil
+}
+
+// Delete implements SessionStore.
+func (s *MemorySessionStore) Delete(shardID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, shardID)
+	return nil
+}
+
+// FileSessionStore is a SessionStore keeping the session states of all shards in a JSON file.
+//
+// The file is replaced atomically and synced to disk on every change, so a crash
+// never leaves it half written. As a BatchSessionStore, it is written once for all
+// the shards of a Client.
+//
+// Usage example:
+//
+//	client := goda.New(ctx,
+//	    goda.WithSessionStore(goda.NewFileSessionStore("sessions.json")),
+//	)
+//
+// Notes:
+//   - Processes of a cluster may share a file only if they never write it concurrently,
+//     prefer one file per process.
+type FileSessionStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+var _ BatchSessionStore = (*FileSessionStore)(nil)
+
+// NewFileSessionStore creates a SessionStore using the JSON file at path.
+//
+// The file is created on the first Save.
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+// Load implements SessionStore.
+func (s *FileSessionStore) Load(shardID int) (SessionState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.read()
+	if err != nil {
+		return SessionState{}, false, err
+	}
+	for _, state := range states {
+		if state.ShardID == shardID {
+			return state, true, nil
+		}
+	}
+	return SessionState{}, false, nil
+}
+
+// LoadAll implements BatchSessionStore.
+func (s *FileSessionStore) LoadAll(shardIDs []int) (map[int]SessionState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[int]struct{}, len(shardIDs))
+	for _, id := range shardIDs {
+		wanted[id] = struct{}{}
+	}
+	loaded := make(map[int]SessionState)
+	for _, state := range states {
+		if _, ok := wanted[state.ShardID]; ok {
+			loaded[state.ShardID] = state
+		}
+	}
+	return loaded, nil
+}
+
+// Save implements SessionStore.
+func (s *FileSessionStore) Save(state SessionState) error {
+	return s.SaveAll([]SessionState{state})
+}
+
+// SaveAll implements BatchSessionStore.
+func (s *FileSessionStore) SaveAll(saved []SessionState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.read()
+	if err != nil {
+		return err
+	}
+	ids := make([]int, len(saved))
+	for i, state := range saved {
+		ids[i] = state.ShardID
+	}
+	states = deleteSessionStates(states, ids)
+	return s.write(append(states, saved...))
+}
+
+// Delete implements SessionStore.
+func (s *FileSessionStore) Delete(shardID int) error {
+	return s.DeleteAll([]int{shardID})
+}
+
+// DeleteAll implements BatchSessionStore.
+func (s *FileSessionStore) DeleteAll(shardIDs []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.read()
+	if err != nil {
+		return err
+	}
+	return s.write(deleteSessionStates(states, shardIDs))
+}
+
+// read returns the states of the file, none if it does not exist.
+func (s *FileSessionStore) read() ([]SessionState, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var states []SessionState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// write replaces the file with states through a temporary file, synced before
+// the rename so the file never holds partial data after a crash.
+func (s *FileSessionStore) write(states []SessionState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	// sync the directory to persist the rename, best effort as not every platform supports it
+	if dir, err := os.Open(filepath.Dir(s.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// deleteSessionStates returns states without the states of shardIDs.
+func deleteSessionStates(states []SessionState, shardIDs []int) []SessionState {
+	deleted := make(map[int]struct{}, len(shardIDs))
+	for _, id := range shardIDs {
+		deleted[id] = struct{}{}
+	}
+	kept := states[:0]
+	for _, state := range states {
+		if _, ok := deleted[state.ShardID]; !ok {
+			kept = append(kept, state)
+		}
+	}
+	return kept
+}
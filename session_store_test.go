@@ -0,0 +1,279 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+func TestFileSessionStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	store := NewFileSessionStore(path)
+
+	if _, ok, err := store.Load(0); err != nil || ok {
+		t.Fatalf("expected empty store, got ok=%v err=%v", ok, err)
+	}
+
+	states := []SessionState{
+		{ShardID: 0, TotalShards: 2, SessionID: "a", Sequence: 10, ResumeURL: "wss://a"},
+		{ShardID: 1, TotalShards: 2, SessionID: "b", Sequence: 20, ResumeURL: "wss://b"},
+	}
+	for _, state := range states {
+		if err := store.Save(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	states[0].Sequence = 11
+	if err := store.Save(states[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new store on the same file acts as a restarted process
+	reopened := NewFileSessionStore(path)
+	for _, want := range states {
+		got, ok, err := reopened.Load(want.ShardID)
+		if err != nil || !ok || got != want {
+			t.Fatalf("shard %d: expected %+v, got %+v (ok=%v err=%v)", want.ShardID, want, got, ok, err)
+		}
+	}
+
+	if err := reopened.Delete(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := store.Load(0); ok {
+		t.Fatal("deleted session still stored")
+	}
+	if _, ok, _ := store.Load(1); !ok {
+		t.Fatal("session of another shard deleted")
+	}
+}
+
+// countingSessionStore counts the calls made to a FileSessionStore.
+type countingSessionStore struct {
+	*FileSessionStore
+	single, batch atomic.Int32
+}
+
+func (s *countingSessionStore) Load(id int) (SessionState, bool, error) {
+	s.single.Add(1)
+	return s.FileSessionStore.Load(id)
+}
+
+func (s *countingSessionStore) Save(state SessionState) error {
+	s.single.Add(1)
+	return s.FileSessionStore.Save(state)
+}
+
+func (s *countingSessionStore) Delete(id int) error {
+	s.single.Add(1)
+	return s.FileSessionStore.Delete(id)
+}
+
+func (s *countingSessionStore) LoadAll(ids []int) (map[int]SessionState, error) {
+	s.batch.Add(1)
+	return s.FileSessionStore.LoadAll(ids)
+}
+
+func (s *countingSessionStore) SaveAll(states []SessionState) error {
+	s.batch.Add(1)
+	return s.FileSessionStore.SaveAll(states)
+}
+
+func (s *countingSessionStore) DeleteAll(ids []int) error {
+	s.batch.Add(1)
+	return s.FileSessionStore.DeleteAll(ids)
+}
+
+func TestClient_SavesSessionsInOneBatch(t *testing.T) {
+	dir := t.TempDir()
+	store := &countingSessionStore{FileSessionStore: NewFileSessionStore(filepath.Join(dir, "sessions.json"))}
+	c := New(context.Background(),
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+		WithSessionStore(store),
+	)
+
+	const totalShards = 50
+	shards := make([]*Shard, totalShards)
+	ids := make([]int, totalShards)
+	for i := range shards {
+		shards[i] = newTestShard("", nil)
+		shards[i].shardID, shards[i].totalShards = i, totalShards
+		shards[i].restoreSession(SessionState{ShardID: i, TotalShards: totalShards, SessionID: "s" + strconv.Itoa(i), Sequence: int64(i + 1)})
+		ids[i] = i
+	}
+	c.saveSessions(shards)
+	if single, batch := store.single.Load(), store.batch.Load(); single != 0 || batch != 1 {
+		t.Fatalf("expected a single batch save, got %d single and %d batch calls", single, batch)
+	}
+
+	stored := c.loadSessions(ids, totalShards)
+	if len(stored) != totalShards || stored[7].SessionID != "s7" {
+		t.Fatalf("expected the %d saved sessions, got %d", totalShards, len(stored))
+	}
+	if single, batch := store.single.Load(), store.batch.Load(); single != 0 || batch != 3 {
+		t.Fatalf("expected a batch load and delete, got %d single and %d batch calls", single, batch-1)
+	}
+	if states, err := store.LoadAll(ids); err != nil || len(states) != 0 {
+		t.Fatalf("expected loaded sessions deleted, got %d (%v)", len(states), err)
+	}
+
+	// the file is replaced through a synced temporary file, never left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected only the session file, got %d files", len(entries))
+	}
+}
+
+func TestShard_ResumesRestoredSession(t *testing.T) {
+	received := make(chan []byte, 1)
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		msg, _, err := wsutil.ReadClientData(conn)
+		if err != nil {
+			return
+		}
+		received <- msg
+	})
+
+	s := newTestShard("", nil)
+	s.restoreSession(SessionState{ShardID: 0, TotalShards: 1, SessionID: "stored", Sequence: 42, ResumeURL: url})
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer s.Shutdown()
+
+	select {
+	case msg := <-received:
+		var payload struct {
+			Op gatewayOpcode `json:"op"`
+			D  struct {
+				SessionID string `json:"session_id"`
+				Seq       int64  `json:"seq"`
+			} `json:"d"`
+		}
+		json.Unmarshal(msg, &payload)
+		if payload.Op != gatewayOpcodeResume || payload.D.SessionID != "stored" || payload.D.Seq != 42 {
+			t.Fatalf("expected resume of the stored session, got %s", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no payload sent after hello")
+	}
+
+	state, ok := s.sessionState()
+	if !ok || state.SessionID != "stored" || state.Sequence != 42 {
+		t.Fatalf("unexpected session state %+v", state)
+	}
+}
+
+func TestClient_ResumedSessionJoinsVoice(t *testing.T) {
+	var selfFetches atomic.Int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v10/gateway/bot":
+			w.Write([]byte(`{"url":"ws://discord.invalid","shards":1,"session_start_limit":` +
+				`{"total":1000,"remaining":1000,"reset_after":0,"max_concurrency":1}}`))
+			return
+		case "/api/v10/users/@me":
+			selfFetches.Add(1)
+			w.Write([]byte(`{"id":"42","username":"bot"}`))
+			return
+		}
+
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		msg, _, err := wsutil.ReadClientData(conn)
+		if err != nil || !strings.Contains(string(msg), `"op":6`) {
+			t.Errorf("expected a resume, got %s (%v)", msg, err)
+			return
+		}
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":11,"t":"RESUMED","d":{}}`))
+		for {
+			msg, _, err := wsutil.ReadClientData(conn)
+			if err != nil {
+				return
+			}
+			if !strings.Contains(string(msg), `"op":4`) {
+				continue
+			}
+			// Discord answers a voice state update with the bot's voice state and the voice server
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":12,"t":"VOICE_STATE_UPDATE","d":`+
+				`{"guild_id":"1","channel_id":"2","user_id":"42","session_id":"voice-session"}}`))
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":13,"t":"VOICE_SERVER_UPDATE","d":`+
+				`{"token":"voice-token","guild_id":"1","endpoint":"voice.invalid:443"}}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	store := NewMemorySessionStore()
+	store.Save(SessionState{ShardID: 0, TotalShards: 1, SessionID: "abc", Sequence: 10, UserID: 42})
+
+	c := New(context.Background(),
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+		WithRestURL(srv.URL+"/api/v10"),
+		WithGatewayURL(strings.Replace(srv.URL, "http://", "ws://", 1)),
+		WithSessionStore(store),
+	)
+	resumed := make(chan struct{}, 1)
+	c.OnShardResumed(func(ShardResumedEvent) { resumed <- struct{}{} })
+	go c.Start()
+	defer c.Shutdown()
+
+	select {
+	case <-resumed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stored session not resumed")
+	}
+	for c.shardForGuild(1) == nil {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	session, err := c.JoinVoiceChannel(ctx, 1, 2, false, true)
+	if err != nil {
+		t.Fatalf("JoinVoiceChannel after resume: %v", err)
+	}
+	if session.UserID != 42 || session.SessionID != "voice-session" || session.Endpoint != "voice.invalid:443" {
+		t.Fatalf("unexpected voice session %+v", session)
+	}
+	if n := selfFetches.Load(); n != 0 {
+		t.Fatalf("expected the bot user ID from the store, fetched it %d times", n)
+	}
+
+	c.Shutdown()
+	if state, ok, _ := store.Load(0); !ok || state.UserID != 42 {
+		t.Fatalf("expected the bot user ID saved with the session, got %+v (ok=%v)", state, ok)
+	}
+}
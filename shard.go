@@ -15,8 +15,14 @@ package goda
 
 import (
 	"context"
+	"errors"
+	"hash/fnv"
+	"io"
 	"net"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -25,6 +31,338 @@ import (
 	"github.com/gobwas/ws/wsutil"
 )
 
+/*******************************
+ * Event Deduplicator
+ *******************************/
+
+// eventDeduplicator detects events received by both shard sets while resharding,
+// so that each event is dispatched once.
+//
+// Only events of guilds served by both sets are deduplicated. An event is identified
+// by its guild, name and payload, and each occurrence is recorded with its source shard
+// and sequence. An occurrence is dropped when it pairs with an occurrence received from
+// the other set, so events Discord sends twice with the same payload are dispatched twice.
+type eventDeduplicator struct {
+	mu      sync.Mutex
+	sets    [2]shardSet                    // old and new shard sets
+	pending map[dedupKey][]dedupOccurrence // occurrences not paired with the other set yet
+	ttl     int64                          // time an occurrence is remembered in nanoseconds
+	inserts int                            // inserts since the last cleanup of expired occurrences
+}
+
+// dedupKey identifies an event received by both shard sets.
+type dedupKey struct {
+	guildID Snowflake
+	event   string
+	hash    uint64 // hash of the payload
+}
+
+// dedupOccurrence is an event received by a shard.
+type dedupOccurrence struct {
+	totalShards int   // identifies the shard set of the shard
+	shardID     int   // shard that received the event
+	seq         int64 // sequence of the event in the shard session
+	expiry      int64 // expiry in monotonic nanoseconds
+}
+
+// shardSet is the set of shards running with the same total shard count.
+type shardSet struct {
+	totalShards int
+	shardIDs    map[int]struct{}
+}
+
+// newShardSet creates the shard set of shards.
+func newShardSet(shards []*Shard) shardSet {
+	set := shardSet{shardIDs: make(map[int]struct{}, len(shards))}
+	for _, shard := range shards {
+		set.totalShards = shard.totalShards
+		set.shardIDs[shard.shardID] = struct{}{}
+	}
+	return set
+}
+
+// serves reports whether a shard of the set receives the events of the guild,
+// events outside of guilds are received by shard 0.
+func (s shardSet) serves(guildID Snowflake) bool {
+	if s.totalShards == 0 {
+		return false
+	}
+	_, ok := s.shardIDs[int((uint64(guildID)>>22)%uint64(s.totalShards))]
+	return ok
+}
+
+// newEventDeduplicator creates a deduplicator for the old and new shard sets,
+// remembering events for ttl.
+func newEventDeduplicator(ttl time.Duration, oldShards, newShards []*Shard) *eventDeduplicator {
+	return &eventDeduplicator{
+		sets:    [2]shardSet{newShardSet(oldShards), newShardSet(newShards)},
+		pending: make(map[dedupKey][]dedupOccurrence),
+		ttl:     int64(ttl),
+	}
+}
+
+// eventGuildID returns the guild of an event, 0 for events outside of guilds.
+func eventGuildID(eventName string, data []byte) Snowflake {
+	var event struct {
+		ID      Snowflake `json:"id"`
+		GuildID Snowflake `json:"guild_id"`
+	}
+	unmarshalEventData(data, &event)
+	switch eventName {
+	case "GUILD_CREATE", "GUILD_UPDATE", "GUILD_DELETE":
+		return event.ID
+	}
+	return event.GuildID
+}
+
+// duplicate reports whether an event received by a shard was already received
+// by the other shard set, or by the same shard with the same sequence,
+// and remembers it otherwise.
+func (d *eventDeduplicator) duplicate(totalShards, shardID int, seq int64, eventName string, data []byte) bool {
+	guildID := eventGuildID(eventName, data)
+	if !d.sets[0].serves(guildID) || !d.sets[1].serves(guildID) {
+		return false
+	}
+
+	h := fnv.New64a()
+	h.Write(data)
+	key := dedupKey{guildID: guildID, event: eventName, hash: h.Sum64()}
+	now := MonotonicNow()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	occurrences := d.pending[key]
+	for i, o := range occurrences {
+		if o.expiry <= now {
+			continue
+		}
+		if o.totalShards != totalShards {
+			// paired with the occurrence of the other set
+			d.pending[key] = append(occurrences[:i:i], occurrences[i+1:]...)
+			return true
+		}
+		if o.shardID == shardID && o.seq == seq {
+			return true
+		}
+	}
+	d.pending[key] = append(occurrences, dedupOccurrence{
+		totalShards: totalShards,
+		shardID:     shardID,
+		seq:         seq,
+		expiry:      now + d.ttl,
+	})
+
+	d.inserts++
+	if d.inserts >= 1024 {
+		d.inserts = 0
+		for k, occurrences := range d.pending {
+			occurrences = slices.DeleteFunc(occurrences, func(o dedupOccurrence) bool { return o.expiry <= now })
+			if len(occurrences) == 0 {
+				delete(d.pending, k)
+			} else {
+				d.pending[k] = occurrences
+			}
+		}
+	}
+	return false
+}
+
+/*******************************
+ *       Shard Status
+ *******************************/
+
+// ShardStatus is the connection status of a shard.
+type ShardStatus int
+
+const (
+	// ShardStatusDisconnected means the shard has no Gateway connection.
+	ShardStatusDisconnected ShardStatus = iota
+
+	// ShardStatusConnecting means the shard is opening a Gateway connection.
+	ShardStatusConnecting
+
+	// ShardStatusIdentifying means the shard is starting a new session.
+	ShardStatusIdentifying
+
+	// ShardStatusResuming means the shard is resuming its session.
+	ShardStatusResuming
+
+	// ShardStatusReady means the shard session is established and receives events.
+	ShardStatusReady
+)
+
+// Is returns true if the status matches the provided status.
+func (s ShardStatus) Is(status ShardStatus) bool {
+	return s == status
+}
+
+// String returns the name of the status.
+func (s ShardStatus) String() string {
+	switch s {
+	case ShardStatusDisconnected:
+		return "disconnected"
+	case ShardStatusConnecting:
+		return "connecting"
+	case ShardStatusIdentifying:
+		return "identifying"
+	case ShardStatusResuming:
+		return "resuming"
+	case ShardStatusReady:
+		return "ready"
+	}
+	return "unknown"
+}
+
+// setStatus updates the status of the shard.
+func (s *Shard) setStatus(status ShardStatus) {
+	s.status.Store(int32(status))
+}
+
+// ID returns the ID of the shard.
+func (s *Shard) ID() int {
+	return s.shardID
+}
+
+// Status returns the connection status of the shard.
+func (s *Shard) Status() ShardStatus {
+	return ShardStatus(s.status.Load())
+}
+
+// Reconnects returns the number of times the shard reconnected.
+func (s *Shard) Reconnects() uint64 {
+	return s.reconnects.Load()
+}
+
+// Resumes returns the number of sessions the shard resumed.
+func (s *Shard) Resumes() uint64 {
+	return s.resumes.Load()
+}
+
+// InvalidSessions returns the number of invalid sessions the shard received.
+func (s *Shard) InvalidSessions() uint64 {
+	return s.invalidSessions.Load()
+}
+
+/*******************************
+ *    Guild Availability
+ *******************************/
+
+// defaultShardReadyTimeout is the default time without guilds after which a shard is ready anyway.
+const defaultShardReadyTimeout = 15 * time.Second
+
+// trackReadyGuilds starts waiting for the guilds listed in the READY data,
+// which arrive as GUILD_CREATE events afterwards.
+func (s *Shard) trackReadyGuilds(data []byte) {
+	var ready struct {
+		Guilds []struct {
+			ID Snowflake `json:"id"`
+		} `json:"guilds"`
+	}
+	unmarshalEventData(data, &ready)
+
+	s.guildsMu.Lock()
+	s.ready.Store(false)
+	s.unavailableGuilds = make(map[Snowflake]struct{}, len(ready.Guilds))
+	for _, guild := range ready.Guilds {
+		s.unavailableGuilds[guild.ID] = struct{}{}
+	}
+	if s.guildsTimer != nil {
+		s.guildsTimer.Stop()
+	}
+	s.guildsTimer = time.AfterFunc(s.readyTimeout, s.guildsTimedOut)
+	s.guildsMu.Unlock()
+
+	if len(ready.Guilds) == 0 {
+		s.finishReady()
+	}
+}
+
+// guildCreated marks the guild of the GUILD_CREATE data available,
+// and reports if the bot joined it instead.
+func (s *Shard) guildCreated(data []byte) (joined bool) {
+	var guild struct {
+		ID Snowflake `json:"id"`
+	}
+	unmarshalEventData(data, &guild)
+
+	s.guildsMu.Lock()
+	_, unavailable := s.unavailableGuilds[guild.ID]
+	delete(s.unavailableGuilds, guild.ID)
+	waiting := s.guildsTimer != nil
+	if waiting {
+		s.guildsTimer.Reset(s.readyTimeout)
+	}
+	remaining := len(s.unavailableGuilds)
+	s.guildsMu.Unlock()
+
+	if waiting && remaining == 0 {
+		s.finishReady()
+	}
+	return !unavailable
+}
+
+// guildDeleted marks the guild of the GUILD_DELETE data unavailable if it is an outage,
+// so its next GUILD_CREATE is not taken for a join.
+func (s *Shard) guildDeleted(data []byte) {
+	var guild struct {
+		ID          Snowflake `json:"id"`
+		Unavailable bool      `json:"unavailable"`
+	}
+	unmarshalEventData(data, &guild)
+	if !guild.Unavailable {
+		return
+	}
+
+	s.guildsMu.Lock()
+	if s.unavailableGuilds == nil {
+		s.unavailableGuilds = make(map[Snowflake]struct{})
+	}
+	s.unavailableGuilds[guild.ID] = struct{}{}
+	s.guildsMu.Unlock()
+}
+
+// sessionResumed makes a shard that resumed a stored session ready, as it receives no READY.
+func (s *Shard) sessionResumed() {
+	s.readyOnce.Do(func() { close(s.readyCh) })
+
+	s.guildsMu.Lock()
+	waiting := s.guildsTimer != nil
+	s.guildsMu.Unlock()
+	if !waiting && !s.ready.Load() {
+		s.finishReady()
+	}
+}
+
+// guildsTimedOut makes the shard ready when guilds stopped arriving.
+func (s *Shard) guildsTimedOut() {
+	s.logger.Warn("Shard " + strconv.Itoa(s.shardID) + " timed out waiting for guilds")
+	s.finishReady()
+}
+
+// finishReady makes the shard ready and reports the guilds still unavailable.
+func (s *Shard) finishReady() {
+	s.guildsMu.Lock()
+	if s.guildsTimer != nil {
+		s.guildsTimer.Stop()
+		s.guildsTimer = nil
+	}
+	if s.ready.Swap(true) {
+		s.guildsMu.Unlock()
+		return
+	}
+	var unavailable []Snowflake
+	for id := range s.unavailableGuilds {
+		unavailable = append(unavailable, id)
+	}
+	s.guildsMu.Unlock()
+
+	s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " ready")
+	if s.onReady != nil && !s.suppressed.Load() {
+		s.onReady(s.shardID, unavailable)
+	}
+}
+
 /*******************************
  * Shards Identify Rate Limiter
  *******************************/
@@ -32,49 +370,346 @@ import (
 // ShardsIdentifyRateLimiter defines the interface for a rate limiter
 // that controls the frequency of Identify payloads sent per shard.
 //
-// Implementations block the caller in Wait() until an Identify token is available.
+// Discord allows one Identify per rate limit key (shard_id % max_concurrency)
+// every 5 seconds, so shards of distinct keys may identify in parallel.
+//
+// Implementations block the caller in Wait until the shard may identify.
+//
+// Reference: https://discord.com/developers/docs/events/gateway#sharding-max-concurrency
 type ShardsIdentifyRateLimiter interface {
-	// Wait blocks until the shard is allowed to send an Identify payload.
-	Wait()
+	// Wait blocks until the shard is allowed to send an Identify payload,
+	// or returns the context error if ctx is done first.
+	Wait(ctx context.Context, shardID int) error
 }
 
-// DefaultShardsRateLimiter implements a simple token bucket
-// rate limiter using a buffered channel of tokens.
-//
-// The capacity and refill interval control the max burst and rate.
+// identifyRateLimitKey returns the identify rate limit key of a shard.
+func identifyRateLimitKey(shardID, maxConcurrency int) int {
+	return shardID % max(maxConcurrency, 1)
+}
+
+// DefaultShardsRateLimiter implements a ShardsIdentifyRateLimiter with one bucket per
+// rate limit key, each allowing one Identify per interval.
 type DefaultShardsRateLimiter struct {
-	tokens chan struct{}
+	maxConcurrency int
+	interval       time.Duration
+	buckets        *MemoryShardsCoordinator
 }
 
 var _ ShardsIdentifyRateLimiter = (*DefaultShardsRateLimiter)(nil)
 
-// NewDefaultShardsRateLimiter creates a new token bucket rate limiter.
+// NewDefaultShardsRateLimiter creates a new bucketed rate limiter.
 //
-// r specifies the maximum burst tokens allowed.
-// interval specifies how frequently tokens are refilled.
-func NewDefaultShardsRateLimiter(r int, interval time.Duration) *DefaultShardsRateLimiter {
-	rl := &DefaultShardsRateLimiter{tokens: make(chan struct{}, r)}
-	// fill initial tokens
-	for range r {
-		rl.tokens <- struct{}{}
+// maxConcurrency is session_start_limit.max_concurrency of the bot (values below 1 use 1),
+// interval is the window of one Identify per bucket (5 seconds for Discord).
+func NewDefaultShardsRateLimiter(maxConcurrency int, interval time.Duration) *DefaultShardsRateLimiter {
+	return &DefaultShardsRateLimiter{
+		maxConcurrency: maxConcurrency,
+		interval:       interval,
+		buckets:        NewMemoryShardsCoordinator(),
 	}
-	// refill tokens periodically in a goroutine
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-		for range ticker.C {
-			select {
-			case rl.tokens <- struct{}{}:
-			default:
+}
+
+// Wait blocks until the bucket of the shard allows sending Identify.
+func (rl *DefaultShardsRateLimiter) Wait(ctx context.Context, shardID int) error {
+	return rl.buckets.AcquireIdentify(ctx, identifyRateLimitKey(shardID, rl.maxConcurrency), rl.interval)
+}
+
+/*******************************
+ *   Session Start Budget
+ *******************************/
+
+// sessionStartBudget tracks the session start limit of the bot,
+// so identifies wait for the limit to reset instead of exhausting it.
+//
+// All methods are no-ops on a nil budget.
+type sessionStartBudget struct {
+	mu      sync.Mutex
+	limit   SessionStartLimit
+	resetAt time.Time                         // time the limit resets, limit.ResetAfter is relative to the last refresh
+	refresh func() (SessionStartLimit, error) // fetches the current limit from Discord, may be nil
+	logger  Logger
+}
+
+// newSessionStartBudget creates a budget starting at limit.
+func newSessionStartBudget(limit SessionStartLimit, refresh func() (SessionStartLimit, error), logger Logger) *sessionStartBudget {
+	b := &sessionStartBudget{refresh: refresh, logger: logger}
+	b.set(limit)
+	return b
+}
+
+// set replaces the tracked limit, the caller must hold b.mu or own b.
+func (b *sessionStartBudget) set(limit SessionStartLimit) {
+	b.limit = limit
+	b.resetAt = time.Now().Add(time.Duration(limit.ResetAfter) * time.Millisecond)
+}
+
+// snapshot returns the tracked limit, with ResetAfter relative to now.
+func (b *sessionStartBudget) snapshot() SessionStartLimit {
+	if b == nil {
+		return SessionStartLimit{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	limit := b.limit
+	limit.ResetAfter = max(int(time.Until(b.resetAt).Milliseconds()), 0)
+	return limit
+}
+
+// available blocks until at least one session start remains, or ctx is done.
+//
+// When the tracked budget is exhausted, it is refreshed from Discord once,
+// then waits for the limit to reset.
+func (b *sessionStartBudget) available(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	refreshed := false
+	for {
+		b.mu.Lock()
+		if !time.Now().Before(b.resetAt) {
+			b.limit.Remaining = b.limit.Total
+			b.resetAt = time.Now().Add(24 * time.Hour)
+		}
+		if b.limit.Remaining > 0 {
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Until(b.resetAt)
+		b.mu.Unlock()
+
+		if !refreshed && b.refresh != nil {
+			refreshed = true
+			if limit, err := b.refresh(); err == nil {
+				b.mu.Lock()
+				b.set(limit)
+				b.mu.Unlock()
+				continue
 			}
 		}
-	}()
-	return rl
+
+		b.logger.Warn("Session start limit exhausted, waiting " + wait.Round(time.Second).String() + " before identifying")
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// take uses one session start, waiting for the limit to reset if none remains.
+func (b *sessionStartBudget) take(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		if err := b.available(ctx); err != nil {
+			return err
+		}
+
+		b.mu.Lock()
+		if b.limit.Remaining > 0 {
+			b.limit.Remaining--
+			remaining, total := b.limit.Remaining, b.limit.Total
+			b.mu.Unlock()
+			if remaining < total/10 {
+				b.logger.Warn("Session start limit low: " + strconv.Itoa(remaining) + "/" + strconv.Itoa(total) + " remaining")
+			}
+			return nil
+		}
+		b.mu.Unlock()
+	}
+}
+
+/*******************************
+ *     Gateway Send Queue
+ *******************************/
+
+const (
+	// gatewaySendLimit is the number of payloads a connection may send per gatewaySendInterval.
+	gatewaySendLimit = 120
+	// gatewaySendInterval is the window of gatewaySendLimit.
+	gatewaySendInterval = 60 * time.Second
+	// gatewayReservedSends is kept out of the budget of normal sends,
+	// leaving room for heartbeats and session payloads in every window.
+	gatewayReservedSends = 10
+)
+
+// gatewaySendLimiter is a fixed window rate limiter for payloads sent on a
+// single Gateway connection. Exceeding the Gateway send limit closes the
+// connection with GatewayCloseEventCodeRateLimited.
+type gatewaySendLimiter struct {
+	limit     int
+	remaining int
+	interval  time.Duration
+	resetAt   time.Time
+}
+
+// newGatewaySendLimiter creates a limiter allowing limit sends per interval.
+func newGatewaySendLimiter(limit int, interval time.Duration) *gatewaySendLimiter {
+	return &gatewaySendLimiter{limit: limit, interval: interval}
+}
+
+// take uses one send if more than reserved sends remain in the current window,
+// otherwise it returns the time until the window resets.
+func (l *gatewaySendLimiter) take(reserved int) (wait time.Duration) {
+	now := time.Now()
+	if !now.Before(l.resetAt) {
+		l.remaining = l.limit
+		l.resetAt = now.Add(l.interval)
+	}
+	if l.remaining > reserved {
+		l.remaining--
+		return 0
+	}
+	return l.resetAt.Sub(now)
+}
+
+// gatewaySend is a payload waiting in a gatewaySendQueue.
+type gatewaySend struct {
+	ctx     context.Context
+	payload []byte
+	result  chan error
+}
+
+// gatewaySendQueue serializes the payloads sent by a shard through a single writer goroutine,
+// enforcing the Gateway send limit.
+//
+// Priority payloads (heartbeats, identify and resume) jump ahead of queued payloads
+// and may use the gatewayReservedSends kept out of the budget of normal payloads.
+type gatewaySendQueue struct {
+	write    func(payload []byte) error
+	limiter  *gatewaySendLimiter
+	priority chan *gatewaySend
+	normal   chan *gatewaySend
+	done     chan struct{}
+	exited   chan struct{} // closed once the writer goroutine returned
+	start    sync.Once
+	stop     sync.Once
+}
+
+// newGatewaySendQueue creates a queue writing payloads with write.
+func newGatewaySendQueue(write func(payload []byte) error) *gatewaySendQueue {
+	return &gatewaySendQueue{
+		write:    write,
+		limiter:  newGatewaySendLimiter(gatewaySendLimit, gatewaySendInterval),
+		priority: make(chan *gatewaySend),
+		normal:   make(chan *gatewaySend),
+		done:     make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+}
+
+// send queues a payload and waits until it is written, returning the write error.
+//
+// Returns the context error if ctx is done before the payload is written,
+// or ErrShardNotConnected if the queue is closed.
+func (q *gatewaySendQueue) send(ctx context.Context, payload []byte, priority bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	q.start.Do(func() { go q.run() })
+
+	req := &gatewaySend{ctx: ctx, payload: payload, result: make(chan error, 1)}
+	ch := q.normal
+	if priority {
+		ch = q.priority
+	}
+
+	select {
+	case ch <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-q.done:
+		return ErrShardNotConnected
+	}
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-q.done:
+		return ErrShardNotConnected
+	}
+}
+
+// close stops the writer goroutine and waits for it to return,
+// pending and later sends fail with ErrShardNotConnected.
+func (q *gatewaySendQueue) close() {
+	q.stop.Do(func() { close(q.done) })
+	// a queue never started has no writer to wait for
+	q.start.Do(func() { close(q.exited) })
+	<-q.exited
+}
+
+// run writes queued payloads until the queue is closed.
+func (q *gatewaySendQueue) run() {
+	defer close(q.exited)
+	for {
+		// priority payloads first
+		select {
+		case req := <-q.priority:
+			q.writeNow(req)
+			continue
+		default:
+		}
+
+		select {
+		case req := <-q.priority:
+			q.writeNow(req)
+		case req := <-q.normal:
+			q.writeNormal(req)
+		case <-q.done:
+			return
+		}
+	}
 }
 
-// Wait blocks until a token is available for sending Identify.
-func (rl *DefaultShardsRateLimiter) Wait() {
-	<-rl.tokens
+// writeNormal writes a normal payload once the limiter allows it,
+// writing priority payloads arriving meanwhile first.
+func (q *gatewaySendQueue) writeNormal(req *gatewaySend) {
+	for {
+		wait := q.limiter.take(gatewayReservedSends)
+		if wait == 0 {
+			req.result <- q.write(req.payload)
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case prio := <-q.priority:
+			timer.Stop()
+			q.writeNow(prio)
+		case <-timer.C:
+		case <-req.ctx.Done():
+			timer.Stop()
+			req.result <- req.ctx.Err()
+			return
+		case <-q.done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// writeNow writes a priority payload once the limiter allows it, using reserved sends if needed.
+func (q *gatewaySendQueue) writeNow(req *gatewaySend) {
+	for {
+		wait := q.limiter.take(0)
+		if wait == 0 {
+			req.result <- q.write(req.payload)
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.ctx.Done():
+			timer.Stop()
+			req.result <- req.ctx.Err()
+			return
+		case <-q.done:
+			timer.Stop()
+			return
+		}
+	}
 }
 
 /*************************************
@@ -82,8 +717,10 @@ func (rl *DefaultShardsRateLimiter) Wait() {
  *************************************/
 
 const (
-	gatewayVersion = "10"
-	gatewayURL     = "wss://gateway.discord.gg/?v=10&encoding=json"
+	// gatewayVersion is fixed, like apiVersion: goda's types model the
+	// payloads of this version, so another one would decode them wrongly.
+	gatewayVersion    = "10"
+	defaultGatewayURL = "wss://gateway.discord.gg"
 )
 
 // Shard manages a single WebSocket connection to Discord Gateway,
@@ -94,91 +731,284 @@ type Shard struct {
 	token       string        // Discord bot token
 	intents     GatewayIntent // Gateway intents bitmask
 
-	logger          Logger                    // logger interface for informational and error messages
-	dispatcher      *dispatcher               // event dispatcher for received Gateway events
-	identifyLimiter ShardsIdentifyRateLimiter // rate limiter controlling Identify payloads
+	gatewayURL   string    // Gateway URL used when there is no session to resume
+	fixedGateway bool      // resume through gatewayURL too, ignoring resume_gateway_url
+	dialer       ws.Dialer // dials the Gateway WebSocket connections
+
+	logger          Logger                       // logger interface for informational and error messages
+	dispatcher      *dispatcher                  // event dispatcher for received Gateway events
+	identifyLimiter ShardsIdentifyRateLimiter    // rate limiter controlling Identify payloads
+	sessionBudget   *sessionStartBudget          // session start limit consumed by identifies, may be nil
+	encoding        GatewayEncoding              // payload encoding negotiated on connect
+	compression     GatewayCompression           // transport compression negotiated on connect
+	newDecompressor GatewayDecompressorFactory   // creates the decompressor of each connection
+	onFatal         func(shardID int, err error) // called when the shard stops on an unrecoverable error
+
+	presence atomic.Pointer[gatewayPresence] // presence sent on identify, updated by SetPresence
+
+	ctx        context.Context    // shard lifetime, cancelled by Shutdown
+	cancel     context.CancelFunc // cancels ctx
+	wg         sync.WaitGroup     // tracks the goroutines of the shard
+	connCancel context.CancelFunc // stops the goroutines of the current connection
+	dropErr    atomic.Pointer[error]
 
-	conn net.Conn // websocket connection
+	writeMu      sync.Mutex          // serializes writes on conn, guards conn, connCancel and sendQueue
+	conn         net.Conn            // websocket connection
+	sendQueue    *gatewaySendQueue   // serializes and rate limits the payloads sent on conn, nil without conn
+	decompressor GatewayDecompressor // transport decompressor of the current connection
 
 	seq       int64  // last received sequence number from Gateway
 	sessionID string // current session id for resuming
 	resumeURL string // Gateway URL to resume session on
 
-	latency          int64       // heartbeat latency in milliseconds
+	latency          int64       // heartbeat round-trip time in nanoseconds
+	heartbeatSent    int64       // monotonic time in nanoseconds of the last heartbeat sent
 	lastHeartbeatACK atomic.Bool // true if last heartbeat was acknowledged
+
+	status          atomic.Int32  // current ShardStatus
+	reconnects      atomic.Uint64 // number of reconnects
+	resumes         atomic.Uint64 // number of sessions resumed
+	invalidSessions atomic.Uint64 // number of invalid sessions received
+
+	suppressed atomic.Bool                       // true while events must not be dispatched (resharding)
+	dedup      atomic.Pointer[eventDeduplicator] // drops events already dispatched by another shard set
+	readyCh    chan struct{}                     // closed once the first READY is received
+	readyOnce  sync.Once
+
+	readyTimeout      time.Duration                              // time without guilds after which the shard is ready anyway
+	onReady           func(shardID int, unavailable []Snowflake) // called when the shard becomes ready
+	ready             atomic.Bool                                // true once the guilds of READY arrived or timed out
+	guildsMu          sync.Mutex
+	unavailableGuilds map[Snowflake]struct{} // guilds that did not become available yet
+	guildsTimer       *time.Timer            // ready timeout, nil when not waiting for guilds
 }
 
-// newShard constructs a new Shard instance with the specified parameters.
+// shardConfig holds the client wide settings shared by every shard.
+type shardConfig struct {
+	ctx             context.Context              // parent of the shard context, may be nil
+	token           string                       // Discord bot token
+	intents         GatewayIntent                // Gateway intents bitmask
+	gatewayURL      string                       // Gateway URL, empty uses the Discord Gateway
+	fixedGateway    bool                         // resume through gatewayURL too
+	dialer          ws.Dialer                    // dials the Gateway WebSocket connections
+	logger          Logger                       // logger for informational and error messages
+	dispatcher      *dispatcher                  // event dispatcher for received Gateway events
+	identifyLimiter ShardsIdentifyRateLimiter    // rate limiter controlling Identify payloads
+	sessionBudget   *sessionStartBudget          // session start limit consumed by identifies, may be nil
+	encoding        GatewayEncoding              // payload encoding, empty uses JSON
+	compression     GatewayCompression           // transport compression
+	newDecompressor GatewayDecompressorFactory   // creates the decompressor of each connection
+	presence        *gatewayPresence             // initial presence sent on identify, may be nil
+	onFatal         func(shardID int, err error) // called when the shard stops on an unrecoverable error
+	onReady         func(int, []Snowflake)       // called when the shard becomes ready, may be nil
+	readyTimeout    time.Duration                // time without guilds after which the shard is ready anyway
+}
+
+// newShard constructs a new Shard instance.
 //
 // shardID and totalShards configure the sharding info,
-// token and url set authentication and gateway endpoint,
-// intents specify Gateway events to receive,
-// logger and dispatcher handle logging and event dispatching,
-// limiter enforces Identify rate limits.
-func newShard(
-	shardID, totalShards int, token string, intents GatewayIntent,
-	logger Logger, dispatcher *dispatcher, limiter ShardsIdentifyRateLimiter,
-) *Shard {
-	return &Shard{
+// cfg holds the settings shared with the other shards of the client.
+func newShard(shardID, totalShards int, cfg shardConfig) *Shard {
+	if cfg.ctx == nil {
+		cfg.ctx = context.Background()
+	}
+	if cfg.gatewayURL == "" {
+		cfg.gatewayURL = defaultGatewayURL
+	}
+	ctx, cancel := context.WithCancel(cfg.ctx)
+	s := &Shard{
+		ctx:             ctx,
+		cancel:          cancel,
 		shardID:         shardID,
 		totalShards:     totalShards,
-		token:           token,
-		intents:         intents,
-		logger:          logger,
-		dispatcher:      dispatcher,
-		identifyLimiter: limiter,
+		token:           cfg.token,
+		intents:         cfg.intents,
+		gatewayURL:      cfg.gatewayURL,
+		fixedGateway:    cfg.fixedGateway,
+		dialer:          cfg.dialer,
+		logger:          cfg.logger,
+		dispatcher:      cfg.dispatcher,
+		identifyLimiter: cfg.identifyLimiter,
+		sessionBudget:   cfg.sessionBudget,
+		encoding:        cfg.encoding,
+		compression:     cfg.compression,
+		newDecompressor: cfg.newDecompressor,
+		onFatal:         cfg.onFatal,
+		onReady:         cfg.onReady,
+		readyTimeout:    cfg.readyTimeout,
+		readyCh:         make(chan struct{}),
+	}
+	if s.readyTimeout <= 0 {
+		s.readyTimeout = defaultShardReadyTimeout
+	}
+	if s.encoding == "" {
+		s.encoding = GatewayEncodingJSON
+	}
+	s.presence.Store(cfg.presence)
+	return s
+}
+
+// gatewayQuery builds the Gateway connection query for the given base url,
+// including the API version, encoding and transport compression.
+func (s *Shard) gatewayQuery(base string) string {
+	url := strings.TrimSuffix(base, "/") + "/?v=" + gatewayVersion + "&encoding=" + string(s.encoding)
+	if s.compression != GatewayCompressionNone {
+		url += "&compress=" + string(s.compression)
 	}
+	return url
+}
+
+// bufferedConn is a connection whose first bytes were already buffered by the dialer.
+type bufferedConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
 }
 
 // Connect establishes or resumes a WebSocket connection to Discord Gateway
 //
 // The shard attempts to connect to the resumeURL if set, otherwise
-// to the default gateway url.
+// to its gateway url (always to it with WithGatewayURL).
 //
-// It spawns a goroutine to read messages asynchronously.
+// It spawns a goroutine to read messages asynchronously, stopped with the connection
+// or when the shard context is cancelled.
 func (s *Shard) connect(ctx context.Context) error {
-	if s.conn != nil {
-		s.conn.Close()
+	if err := s.ctx.Err(); err != nil {
+		return err
 	}
+	s.closeConn()
 
 	url := s.resumeURL
-	if url == "" {
-		url = gatewayURL
+	if url == "" || s.fixedGateway {
+		url = s.gatewayURL
 	}
 
-	dialer := ws.Dialer{}
-
-	conn, _, _, err := dialer.Dial(ctx, url)
+	s.setStatus(ShardStatusConnecting)
+	conn, br, _, err := s.dialer.Dial(ctx, s.gatewayQuery(url))
 	if err != nil {
+		s.setStatus(ShardStatusDisconnected)
 		return err
 	}
+	if br != nil {
+		// the Gateway sent Hello along with the handshake response
+		conn = &bufferedConn{Conn: conn, r: io.MultiReader(br, conn)}
+	}
 
-	s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " connected")
-	s.conn = conn
+	// every connection starts a new compressed stream
+	if s.decompressor != nil {
+		s.decompressor.Close()
+		s.decompressor = nil
+	}
+	if s.newDecompressor != nil {
+		s.decompressor = s.newDecompressor()
+	}
 	s.lastHeartbeatACK.Store(true)
+	s.dropErr.Store(nil)
+
+	s.writeMu.Lock()
+	if err := s.ctx.Err(); err != nil {
+		// shut down while dialing
+		s.writeMu.Unlock()
+		conn.Close()
+		return err
+	}
+	connCtx, connCancel := context.WithCancel(s.ctx)
+	s.conn = conn
+	s.connCancel = connCancel
+	// every connection starts with an empty queue and a full send budget
+	s.sendQueue = newGatewaySendQueue(func(payload []byte) error {
+		return s.writePayload(conn, payload)
+	})
+	s.wg.Add(1)
+	s.writeMu.Unlock()
 
-	go s.readLoop()
+	s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " connected")
+	dispatchLocal(s.dispatcher, s.shardID, "SHARD_CONNECT", ShardConnectEvent{ShardsID: s.shardID})
+
+	go func() {
+		defer s.wg.Done()
+		s.readLoop(connCtx, conn)
+	}()
 	return nil
 }
 
-// readLoop continuously reads messages from the Gateway WebSocket
+// closeConn stops the goroutines of the current connection and closes it.
+//
+// Payloads still queued on the connection fail with ErrShardNotConnected,
+// they are never written on the next connection.
+func (s *Shard) closeConn() {
+	s.writeMu.Lock()
+	if s.connCancel != nil {
+		s.connCancel()
+		s.connCancel = nil
+	}
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	queue := s.sendQueue
+	s.sendQueue = nil
+	s.writeMu.Unlock()
+
+	// the writer of the queue may be waiting for writeMu
+	if queue != nil {
+		queue.close()
+		s.dispatcher.guildMembersChunk().failShard(s.shardID, ErrMembersRequestInterrupted)
+	}
+}
+
+// drop closes conn because of err, its read loop then reports err and reconnects.
+func (s *Shard) drop(conn net.Conn, err error) {
+	s.dropErr.Store(&err)
+	conn.Close()
+}
+
+// readLoop continuously reads messages from the Gateway WebSocket conn until ctx is done
 //
 // It handles Gateway opcodes, dispatches events, and triggers reconnects as needed.
-func (s *Shard) readLoop() {
+func (s *Shard) readLoop(ctx context.Context, conn net.Conn) {
 	for {
-		msg, op, err := wsutil.ReadServerData(s.conn)
+		msg, op, err := wsutil.ReadServerData(conn)
 		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if dropped := s.dropErr.Swap(nil); dropped != nil {
+				err = *dropped
+			}
+			var closed wsutil.ClosedError
+			if errors.As(err, &closed) {
+				s.handleClose(GatewayCloseEventCode(closed.Code), closed.Reason)
+				return
+			}
 			s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " read error: " + err.Error())
-			s.reconnect()
+			s.reconnect(0, err)
 			return
 		}
 
-		if op != ws.OpText {
+		switch {
+		case op == ws.OpBinary && s.decompressor != nil:
+			msg, err = s.decompressor.Decompress(msg)
+			if err != nil {
+				s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " decompress error: " + err.Error())
+				s.reconnect(0, err)
+				return
+			}
+			if msg == nil {
+				// partial payload, wait for the rest of it
+				continue
+			}
+		case op == ws.OpBinary && s.encoding == GatewayEncodingETF:
+			// uncompressed ETF payload
+		case op != ws.OpText:
 			continue
 		}
 
 		var payload gatewayPayload
-		if err := json.Unmarshal(msg, &payload); err != nil {
+		if err := unmarshalEventData(msg, &payload); err != nil {
 			s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " unmarshal error: " + err.Error())
 			continue
 		}
@@ -186,92 +1016,219 @@ func (s *Shard) readLoop() {
 		switch payload.Op {
 		case gatewayOpcodeDispatch:
 			atomic.StoreInt64(&s.seq, payload.S)
-			s.dispatcher.dispatch(s.shardID, payload.T, payload.D)
+			switch payload.T {
+			case "GUILD_CREATE":
+				joined := s.guildCreated(payload.D)
+				if s.shouldDispatch(payload.T, payload.D) {
+					s.dispatcher.dispatchGuildCreate(s.shardID, payload.D, joined)
+				}
+			case "GUILD_DELETE":
+				s.guildDeleted(payload.D)
+				s.dispatch(payload.T, payload.D)
+			case "RESUMED":
+				s.setStatus(ShardStatusReady)
+				s.resumes.Add(1)
+				dispatchLocal(s.dispatcher, s.shardID, "SHARD_RESUMED", ShardResumedEvent{ShardsID: s.shardID})
+				s.dispatch(payload.T, payload.D)
+				s.sessionResumed()
+			default:
+				s.dispatch(payload.T, payload.D)
+			}
 
 			if payload.T == "READY" {
 				var ready struct {
 					SessionID string `json:"session_id"`
 					ResumeURL string `json:"resume_gateway_url"`
 				}
-				json.Unmarshal(payload.D, &ready)
+				unmarshalEventData(payload.D, &ready)
 				s.sessionID = ready.SessionID
 				s.resumeURL = ready.ResumeURL
+				s.setStatus(ShardStatusReady)
 				s.logger.Debug("Shard " + strconv.Itoa(s.shardID) + " session established")
+				s.readyOnce.Do(func() { close(s.readyCh) })
+				s.trackReadyGuilds(payload.D)
 			}
 
 		case gatewayOpcodeReconnect:
 			s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " RECONNECT received")
-			s.reconnect()
+			s.reconnect(0, ErrReconnectRequested)
+			return
 
 		case gatewayOpcodeInvalidSession:
+			s.invalidSessions.Add(1)
 			var resumable bool
-			json.Unmarshal(payload.D, &resumable)
-			time.Sleep(time.Second)
+			unmarshalEventData(payload.D, &resumable)
+			dispatchLocal(s.dispatcher, s.shardID, "INVALID_SESSION", InvalidSessionEvent{ShardsID: s.shardID, Resumable: resumable})
+			if err := sleepContext(ctx, time.Second); err != nil {
+				return
+			}
 			if resumable {
 				s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " session invalid (resumable), resuming")
-				s.sendResume()
+				s.sendResume(ctx)
 			} else {
 				s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " session invalid (non-resumable), identifying")
-				s.sessionID = ""
-				s.seq = 0
-				s.sendIdentify()
+				s.resetSession()
+				s.sendIdentify(ctx)
 			}
 
 		case gatewayOpcodeHello:
 			var hello struct {
 				HeartbeatInterval float64 `json:"heartbeat_interval"`
 			}
-			json.Unmarshal(payload.D, &hello)
+			unmarshalEventData(payload.D, &hello)
 			interval := time.Duration(hello.HeartbeatInterval) * time.Millisecond
 			s.logger.Debug("Shard " + strconv.Itoa(s.shardID) + " HELLO received, heartbeat " + interval.String())
-			go s.startHeartbeat(interval)
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.startHeartbeat(ctx, conn, interval)
+			}()
 
 			if s.sessionID != "" && atomic.LoadInt64(&s.seq) > 0 {
 				s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " resuming session")
-				s.sendResume()
+				s.sendResume(ctx)
 			} else {
 				s.logger.Debug("Shard " + strconv.Itoa(s.shardID) + " identifying new session")
-				s.sendIdentify()
+				s.sendIdentify(ctx)
 			}
 
 		case gatewayOpcodeHeartbeatACK:
 			s.lastHeartbeatACK.Store(true)
-			atomic.StoreInt64(&s.latency, time.Now().UnixMilli())
+			if sent := atomic.LoadInt64(&s.heartbeatSent); sent != 0 {
+				atomic.StoreInt64(&s.latency, MonotonicNow()-sent)
+			}
 			s.logger.Debug("Shard " + strconv.Itoa(s.shardID) + " heartbeatACK received")
 
 		case gatewayOpcodeHeartbeat:
-			s.sendHeartbeat()
+			s.sendHeartbeat(ctx)
 		}
 	}
 }
 
+// dispatch sends a Gateway event to the dispatcher, unless the shard is suppressed
+// or another shard set already dispatched the same event.
+func (s *Shard) dispatch(eventName string, data []byte) {
+	if s.shouldDispatch(eventName, data) {
+		s.dispatcher.dispatch(s.shardID, eventName, data)
+	}
+}
+
+// shouldDispatch reports if an event must be dispatched,
+// events are dropped while resharding suppresses them or when already dispatched by another shard.
+func (s *Shard) shouldDispatch(eventName string, data []byte) bool {
+	if s.suppressed.Load() {
+		return false
+	}
+	dedup := s.dedup.Load()
+	// the read loop stores the sequence of the event before dispatching it
+	return dedup == nil || !dedup.duplicate(s.totalShards, s.shardID, atomic.LoadInt64(&s.seq), eventName, data)
+}
+
+// waitReady blocks until the shard received READY or ctx is done.
+func (s *Shard) waitReady(ctx context.Context) error {
+	select {
+	case <-s.readyCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleClose handles the Gateway closing the connection with a close code.
+//
+// Fatal codes stop the shard and are reported through onFatal, codes invalidating
+// the session make the shard identify again, others resume the session.
+func (s *Shard) handleClose(code GatewayCloseEventCode, reason string) {
+	s.logger.Warn("Shard " + strconv.Itoa(s.shardID) + " closed by gateway with code " +
+		strconv.Itoa(int(code)) + ": " + reason)
+
+	err := &GatewayCloseError{ShardID: s.shardID, Code: code, Reason: reason}
+	if !code.Reconnectable() {
+		s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " stopped, close code " + strconv.Itoa(int(code)) + " is not reconnectable")
+		s.closeConn()
+		s.setStatus(ShardStatusDisconnected)
+		dispatchLocal(s.dispatcher, s.shardID, "SHARD_DISCONNECT", ShardDisconnectEvent{ShardsID: s.shardID, Code: code, Err: err})
+		if s.onFatal != nil {
+			s.onFatal(s.shardID, err)
+		}
+		return
+	}
+
+	if code.ResetsSession() {
+		s.resetSession()
+	}
+	s.reconnect(code, err)
+}
+
+// resetSession forgets the current session, so the next connection identifies.
+func (s *Shard) resetSession() {
+	s.sessionID = ""
+	s.resumeURL = ""
+	atomic.StoreInt64(&s.seq, 0)
+}
+
+// sessionState returns the resumable state of the shard session,
+// ok is false if the shard has no session.
+func (s *Shard) sessionState() (state SessionState, ok bool) {
+	seq := atomic.LoadInt64(&s.seq)
+	if s.sessionID == "" || seq == 0 {
+		return SessionState{}, false
+	}
+	return SessionState{
+		ShardID:     s.shardID,
+		TotalShards: s.totalShards,
+		SessionID:   s.sessionID,
+		Sequence:    seq,
+		ResumeURL:   s.resumeURL,
+	}, true
+}
+
+// restoreSession sets a stored session, so the next connection resumes it.
+func (s *Shard) restoreSession(state SessionState) {
+	s.sessionID = state.SessionID
+	s.resumeURL = state.ResumeURL
+	atomic.StoreInt64(&s.seq, state.Sequence)
+}
+
 // sendIdentify sends an Identify payload to Discord Gateway
 //
 // This authenticates the shard as a new session and requests events based on intents.
 //
-// Identify payloads are rate limited via identifyLimiter.
-func (s *Shard) sendIdentify() error {
+// Identify payloads use the session start budget and are rate limited via identifyLimiter,
+// waiting until ctx is done at most.
+func (s *Shard) sendIdentify(ctx context.Context) error {
+	s.setStatus(ShardStatusIdentifying)
+	identify := map[string]any{
+		"token": s.token,
+		"properties": map[string]string{
+			"os":      "linux",
+			"browser": LIB_NAME,
+			"device":  LIB_NAME,
+		},
+		"shards":  [2]int{s.shardID, s.totalShards},
+		"intents": s.intents,
+	}
+	if presence := s.presence.Load(); presence != nil {
+		identify["presence"] = presence
+	}
 	payload, _ := json.Marshal(map[string]any{
 		"op": gatewayOpcodeIdentify,
-		"d": map[string]any{
-			"token": s.token,
-			"properties": map[string]string{
-				"os":      "linux",
-				"browser": LIB_NAME,
-				"device":  LIB_NAME,
-			},
-			"shards":  [2]int{s.shardID, s.totalShards},
-			"intents": s.intents,
-		},
+		"d":  identify,
 	})
-	s.identifyLimiter.Wait()
-	return wsutil.WriteClientMessage(s.conn, ws.OpText, payload)
+	if err := s.sessionBudget.take(ctx); err != nil {
+		return err
+	}
+	if err := s.identifyLimiter.Wait(ctx, s.shardID); err != nil {
+		return err
+	}
+	return s.send(ctx, payload, true)
 }
 
 // sendResume sends a Resume payload to Discord Gateway
 //
 // This attempts to resume a previous session using sessionID and sequence number.
-func (s *Shard) sendResume() error {
+func (s *Shard) sendResume(ctx context.Context) error {
+	s.setStatus(ShardStatusResuming)
 	payload, _ := json.Marshal(map[string]any{
 		"op": gatewayOpcodeResume,
 		"d": map[string]any{
@@ -280,60 +1237,231 @@ func (s *Shard) sendResume() error {
 			"seq":        atomic.LoadInt64(&s.seq),
 		},
 	})
-	return wsutil.WriteClientMessage(s.conn, ws.OpText, payload)
+	return s.send(ctx, payload, true)
 }
 
 // sendHeartbeat sends a Heartbeat payload to Discord Gateway
 //
 // The payload data is the last sequence number received.
-func (s *Shard) sendHeartbeat() error {
+func (s *Shard) sendHeartbeat(ctx context.Context) error {
 	payload, _ := json.Marshal(map[string]any{
 		"op": gatewayOpcodeHeartbeat,
 		"d":  atomic.LoadInt64(&s.seq),
 	})
-	return wsutil.WriteClientMessage(s.conn, ws.OpText, payload)
+	atomic.StoreInt64(&s.heartbeatSent, MonotonicNow())
+	return s.send(ctx, payload, true)
+}
+
+// SetPresence updates the presence of the bot on this shard.
+//
+// The presence is also kept and sent when the shard identifies again.
+// Presence updates are rate limited to stay within the Gateway send limit,
+// so this call may block.
+//
+// Usage example:
+//
+//	err := shard.SetPresence(ctx, goda.StatusTypeIdle, goda.Activity{
+//	    Name: "the stars",
+//	    Type: goda.ActivityTypeWatching,
+//	})
+//
+// Returns:
+//   - error: ErrShardNotConnected if the shard has no connection, the context error
+//     if ctx is done while waiting for the rate limit, or the write error.
+func (s *Shard) SetPresence(ctx context.Context, status StatusType, activities ...Activity) error {
+	presence := newGatewayPresence(status, activities)
+	s.presence.Store(presence)
+
+	payload, _ := json.Marshal(map[string]any{
+		"op": gatewayOpcodePresenceUpdate,
+		"d":  presence,
+	})
+	return s.sendLimited(ctx, payload)
+}
+
+// RequestGuildMembersOptions configures a Request Guild Members Gateway command.
+//
+// Reference: https://discord.com/developers/docs/events/gateway-events#request-guild-members
+type RequestGuildMembersOptions struct {
+	// Query returns the members whose username starts with it,
+	// an empty Query with Limit 0 returns all members.
+	//
+	// Note:
+	//   - Requesting all members requires the GatewayIntentGuildMembers intent.
+	Query string
+
+	// Limit is the maximum number of members to return, 0 means no limit.
+	Limit int
+
+	// Presences requests the presences of the members,
+	// requires the GatewayIntentGuildPresences intent.
+	Presences bool
+
+	// UserIDs returns the members with these IDs instead of using Query.
+	//
+	// Optional:
+	//   - Up to 100 IDs, must be empty if Query is set.
+	UserIDs []Snowflake
+}
+
+// requestGuildMembers sends a Request Guild Members payload for the guild (opcode 8).
+//
+// The answer arrives as GUILD_MEMBERS_CHUNK events carrying the given nonce.
+func (s *Shard) requestGuildMembers(ctx context.Context, guildID Snowflake, opts RequestGuildMembersOptions, nonce string) error {
+	data := map[string]any{
+		"guild_id":  guildID,
+		"presences": opts.Presences,
+		"nonce":     nonce,
+	}
+	if len(opts.UserIDs) > 0 {
+		data["user_ids"] = opts.UserIDs
+	} else {
+		data["query"] = opts.Query
+		data["limit"] = opts.Limit
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"op": gatewayOpcodeRequestGuildMembers,
+		"d":  data,
+	})
+	return s.sendLimited(ctx, payload)
+}
+
+// updateVoiceState sends a Voice State Update payload for the guild (opcode 4).
+//
+// A nil channelID disconnects the bot from voice in the guild.
+func (s *Shard) updateVoiceState(ctx context.Context, guildID Snowflake, channelID *Snowflake, mute, deaf bool) error {
+	payload, _ := json.Marshal(map[string]any{
+		"op": gatewayOpcodeVoiceStateUpdate,
+		"d": map[string]any{
+			"guild_id":   guildID,
+			"channel_id": channelID,
+			"self_mute":  mute,
+			"self_deaf":  deaf,
+		},
+	})
+	return s.sendLimited(ctx, payload)
+}
+
+// requestSoundboardSounds sends a Request Soundboard Sounds payload for the guilds (opcode 31).
+//
+// The answer arrives as a SOUNDBOARD_SOUNDS event per guild.
+func (s *Shard) requestSoundboardSounds(ctx context.Context, guildIDs []Snowflake) error {
+	payload, _ := json.Marshal(map[string]any{
+		"op": gatewayOpcodeRequestSoundboardSounds,
+		"d": map[string]any{
+			"guild_ids": guildIDs,
+		},
+	})
+	return s.sendLimited(ctx, payload)
+}
+
+// sendLimited queues a payload behind the other payloads of the shard, waiting until it is written.
+//
+// Payloads sent by user code (presence, voice state, member requests...) go through it,
+// so they never exceed the Gateway send limit.
+func (s *Shard) sendLimited(ctx context.Context, payload []byte) error {
+	return s.send(ctx, payload, false)
+}
+
+// send queues a payload on the current connection, waiting until it is written.
+func (s *Shard) send(ctx context.Context, payload []byte, priority bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	queue := s.sendQueue
+	s.writeMu.Unlock()
+	if queue == nil {
+		return ErrShardNotConnected
+	}
+	return queue.send(ctx, payload, priority)
+}
+
+// writePayload writes a JSON encoded payload to the Gateway on conn,
+// transcoding it into ETF first with the ETF encoding.
+//
+// Returns ErrShardNotConnected if conn is no longer the connection of the shard.
+func (s *Shard) writePayload(conn net.Conn, payload []byte) error {
+	op := ws.OpText
+	if s.encoding == GatewayEncodingETF {
+		var err error
+		if payload, err = jsonToETF(payload); err != nil {
+			return err
+		}
+		op = ws.OpBinary
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.conn == nil || s.conn != conn {
+		return ErrShardNotConnected
+	}
+	return wsutil.WriteClientMessage(conn, op, payload)
 }
 
-// startHeartbeat begins sending heartbeats at the given interval
+// startHeartbeat sends heartbeats on conn at the given interval until ctx is done
 //
 // If a heartbeat ACK is not received before the next heartbeat,
-// the shard reconnects automatically.
-func (s *Shard) startHeartbeat(interval time.Duration) {
+// the connection is dropped and its read loop reconnects.
+func (s *Shard) startHeartbeat(ctx context.Context, conn net.Conn, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
 		if !s.lastHeartbeatACK.Load() {
 			s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " heartbeat not ACKed, reconnecting")
-			s.reconnect()
+			s.drop(conn, ErrHeartbeatTimeout)
 			return
 		}
 
 		s.lastHeartbeatACK.Store(false)
 
-		start := time.Now()
-		if err := s.sendHeartbeat(); err != nil {
+		if err := s.sendHeartbeat(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
 			s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " heartbeat error: " + err.Error())
-			s.reconnect()
+			s.drop(conn, err)
 			return
 		}
-
-		atomic.StoreInt64(&s.latency, time.Since(start).Milliseconds())
 	}
 }
 
 // reconnect closes the current connection and attempts to reconnect
 //
+// code and err are the reason of the disconnect, reported to OnShardDisconnect handlers.
+//
 // Uses exponential backoff on reconnect failures, maxing out at 1 minute.
-func (s *Shard) reconnect() {
-	if s.conn != nil {
-		s.conn.Close()
+// Gives up once the shard context is cancelled.
+func (s *Shard) reconnect(code GatewayCloseEventCode, err error) {
+	if s.ctx.Err() != nil {
+		return
 	}
+	s.closeConn()
+	s.setStatus(ShardStatusDisconnected)
+	s.reconnects.Add(1)
+	dispatchLocal(s.dispatcher, s.shardID, "SHARD_DISCONNECT", ShardDisconnectEvent{ShardsID: s.shardID, Code: code, Err: err})
 
 	backoff := time.Second
-	for {
-		time.Sleep(backoff)
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	for attempt := 1; ; attempt++ {
+		if sleepContext(s.ctx, backoff) != nil {
+			return
+		}
+		dispatchLocal(s.dispatcher, s.shardID, "SHARD_RECONNECTING", ShardReconnectingEvent{ShardsID: s.shardID, Attempt: attempt})
+		if s.sessionID == "" {
+			// the new connection identifies, wait for the session start limit before dialing
+			if s.sessionBudget.available(s.ctx) != nil {
+				return
+			}
+		}
+		ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 		err := s.connect(ctx)
 		cancel()
 
@@ -341,29 +1469,57 @@ func (s *Shard) reconnect() {
 			s.logger.Debug("Shard " + strconv.Itoa(s.shardID) + " reconnected")
 			return
 		}
-
-		s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " reconnect failed, retrying")
-		if backoff < 10*time.Second {
-			backoff += 2
+		if s.ctx.Err() != nil {
+			return
 		}
+
+		s.logger.Error("Shard " + strconv.Itoa(s.shardID) + " reconnect failed, retrying in " + backoff.String())
+		backoff = min(backoff*2, time.Minute)
 	}
 }
 
-// Latency returns the current heartbeat latency in milliseconds
+// Latency returns the heartbeat round-trip time in milliseconds,
+// measured between the last heartbeat sent and its ACK.
+//
+// Returns 0 until the first heartbeat is acknowledged.
 func (s *Shard) Latency() int64 {
-	return atomic.LoadInt64(&s.latency)
+	return time.Duration(atomic.LoadInt64(&s.latency)).Milliseconds()
 }
 
 // Shutdown cleanly closes the shard's websocket connection.
 //
+// It cancels the shard context, stopping its read loop, heartbeat and reconnect attempts,
+// and returns once every goroutine of the shard has exited.
+//
 // Call this when you want to stop the shard gracefully.
 func (s *Shard) Shutdown() error {
-	if s.conn != nil {
+	s.writeMu.Lock()
+	s.cancel()
+	conn := s.conn
+	s.conn = nil
+	s.connCancel = nil
+	queue := s.sendQueue
+	s.sendQueue = nil
+	s.writeMu.Unlock()
+
+	s.setStatus(ShardStatusDisconnected)
+	s.guildsMu.Lock()
+	if s.guildsTimer != nil {
+		s.guildsTimer.Stop()
+		s.guildsTimer = nil
+	}
+	s.guildsMu.Unlock()
+
+	var err error
+	if conn != nil {
 		s.logger.Info("Shard " + strconv.Itoa(s.shardID) + " shutting down")
-		return s.conn.Close()
+		dispatchLocal(s.dispatcher, s.shardID, "SHARD_DISCONNECT", ShardDisconnectEvent{ShardsID: s.shardID})
+		err = conn.Close()
 	}
-	s.conn = nil
-	s.logger = nil
-	s.dispatcher = nil
-	return nil
+	if queue != nil {
+		queue.close()
+		s.dispatcher.guildMembersChunk().failShard(s.shardID, ErrMembersRequestInterrupted)
+	}
+	s.wg.Wait()
+	return err
 }
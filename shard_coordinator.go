@@ -0,0 +1,229 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*****************************
+ *    Shards Coordinator
+ *****************************/
+
+// ShardsCoordinator coordinates the processes running shards of the same bot,
+// so that together they stay within the identify concurrency budget
+// (session_start_limit.max_concurrency identifies per 5 seconds).
+//
+// Every process of a cluster must use a coordinator sharing the same state,
+// e.g. FileShardsCoordinator instances using the same directory.
+//
+// Implementations must be safe for concurrent use.
+type ShardsCoordinator interface {
+	// AcquireIdentify blocks until no Identify was sent with key during the last interval
+	// by any process sharing the coordinator state, then records one sent now.
+	//
+	// Returns the context error if ctx is done first.
+	AcquireIdentify(ctx context.Context, key int, interval time.Duration) error
+}
+
+// MemoryShardsCoordinator is a ShardsCoordinator sharing the identify budget
+// between the shards of a single process.
+//
+// It is the default coordinator of the client.
+type MemoryShardsCoordinator struct {
+	mu   sync.Mutex
+	next map[int]int64 // key -> monotonic time in nanoseconds of the next allowed identify
+}
+
+var _ ShardsCoordinator = (*MemoryShardsCoordinator)(nil)
+
+// NewMemoryShardsCoordinator creates a new in-process ShardsCoordinator.
+func NewMemoryShardsCoordinator() *MemoryShardsCoordinator {
+	return &MemoryShardsCoordinator{next: make(map[int]int64)}
+}
+
+// AcquireIdentify implements ShardsCoordinator.
+func (c *MemoryShardsCoordinator) AcquireIdentify(ctx context.Context, key int, interval time.Duration) error {
+	c.mu.Lock()
+	now := MonotonicNow()
+	at := max(c.next[key], now)
+	c.next[key] = at + int64(interval)
+	c.mu.Unlock()
+
+	return sleepContext(ctx, time.Duration(at-now))
+}
+
+// FileShardsCoordinator is a ShardsCoordinator sharing the identify budget
+// between the processes of a host (or of hosts sharing a filesystem) through files in a directory.
+//
+// Each key uses a state file holding the time of its last Identify,
+// guarded by a lock file created exclusively.
+//
+// Notes:
+//   - Processes must have synchronized clocks, as the state holds wall clock times.
+//   - Lock files left by a crashed process are removed after 30 seconds.
+type FileShardsCoordinator struct {
+	dir string
+}
+
+var _ ShardsCoordinator = (*FileShardsCoordinator)(nil)
+
+const (
+	// fileCoordinatorStaleLock is the age after which a lock file is considered abandoned.
+	fileCoordinatorStaleLock = 30 * time.Second
+	// fileCoordinatorPoll is the delay between attempts to take a lock file.
+	fileCoordinatorPoll = 50 * time.Millisecond
+)
+
+// NewFileShardsCoordinator creates a ShardsCoordinator storing its state in dir,
+// creating the directory if needed.
+//
+// Usage example:
+//
+//	coordinator, err := goda.NewFileShardsCoordinator("/var/run/mybot")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client := goda.New(ctx,
+//	    goda.WithShardRange(0, 15, 32),
+//	    goda.WithShardsCoordinator(coordinator),
+//	)
+func NewFileShardsCoordinator(dir string) (*FileShardsCoordinator, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileShardsCoordinator{dir: dir}, nil
+}
+
+// AcquireIdentify implements ShardsCoordinator.
+func (c *FileShardsCoordinator) AcquireIdentify(ctx context.Context, key int, interval time.Duration) error {
+	statePath := filepath.Join(c.dir, "identify-"+strconv.Itoa(key))
+	lockPath := statePath + ".lock"
+
+	for {
+		if err := c.lock(ctx, lockPath); err != nil {
+			return err
+		}
+
+		now := time.Now().UnixNano()
+		var last int64
+		if data, err := os.ReadFile(statePath); err == nil {
+			last, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			os.Remove(lockPath)
+			return err
+		}
+
+		if wait := last + int64(interval) - now; wait > 0 {
+			os.Remove(lockPath)
+			if err := sleepContext(ctx, time.Duration(wait)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := os.WriteFile(statePath, []byte(strconv.FormatInt(now, 10)), 0o644)
+		os.Remove(lockPath)
+		return err
+	}
+}
+
+// lock creates the lock file at path, waiting while another process holds it.
+func (c *FileShardsCoordinator) lock(ctx context.Context, path string) error {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			return f.Close()
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > fileCoordinatorStaleLock {
+			os.Remove(path)
+			continue
+		}
+		if err := sleepContext(ctx, fileCoordinatorPoll); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepContext waits for d or until ctx is done, returning the context error in that case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+/*****************************
+ * Coordinated Rate Limiter
+ *****************************/
+
+// CoordinatedShardsRateLimiter is a ShardsIdentifyRateLimiter
+// taking identify slots from a ShardsCoordinator.
+//
+// Shards use the key of their rate limit bucket (shard_id % max_concurrency), each allowing
+// one Identify per interval, so processes sharing the coordinator stay within the budget together.
+type CoordinatedShardsRateLimiter struct {
+	coordinator    ShardsCoordinator
+	maxConcurrency int
+	interval       time.Duration
+	logger         Logger
+}
+
+var _ ShardsIdentifyRateLimiter = (*CoordinatedShardsRateLimiter)(nil)
+
+// NewCoordinatedShardsRateLimiter creates a rate limiter using coordinator.
+//
+// maxConcurrency is session_start_limit.max_concurrency of the bot (values below 1 use 1),
+// interval is the window of one identify per key (5 seconds for Discord).
+// Coordinator errors are logged to logger, which may be nil.
+func NewCoordinatedShardsRateLimiter(coordinator ShardsCoordinator, maxConcurrency int, interval time.Duration, logger Logger) *CoordinatedShardsRateLimiter {
+	return &CoordinatedShardsRateLimiter{
+		coordinator:    coordinator,
+		maxConcurrency: max(maxConcurrency, 1),
+		interval:       interval,
+		logger:         logger,
+	}
+}
+
+// Wait blocks until the identify slot of the shard bucket is taken from the coordinator.
+//
+// If the coordinator fails, Wait logs the error and waits a full interval instead.
+func (rl *CoordinatedShardsRateLimiter) Wait(ctx context.Context, shardID int) error {
+	key := identifyRateLimitKey(shardID, rl.maxConcurrency)
+	err := rl.coordinator.AcquireIdentify(ctx, key, rl.interval)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	if rl.logger != nil {
+		rl.logger.WithField("err", err).Error("Shards coordinator failed, waiting " + rl.interval.String() + " before identify")
+	}
+	return sleepContext(ctx, rl.interval)
+}
@@ -0,0 +1,92 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShardsCoordinators_ShareIdentifyKey(t *testing.T) {
+	const interval = 200 * time.Millisecond
+
+	fileA, err := NewFileShardsCoordinator(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a second instance on the same directory acts as another process
+	fileB := &FileShardsCoordinator{dir: fileA.dir}
+	memory := NewMemoryShardsCoordinator()
+
+	tests := []struct {
+		name  string
+		first ShardsCoordinator
+		next  ShardsCoordinator
+	}{
+		{"memory", memory, memory},
+		{"file", fileA, fileB},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			start := time.Now()
+			if err := tt.first.AcquireIdentify(ctx, 0, interval); err != nil {
+				t.Fatal(err)
+			}
+			// another key is not limited
+			if err := tt.next.AcquireIdentify(ctx, 1, interval); err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed >= interval {
+				t.Fatalf("distinct keys waited %v", elapsed)
+			}
+			if err := tt.next.AcquireIdentify(ctx, 0, interval); err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed < interval {
+				t.Fatalf("same key acquired again after %v", elapsed)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, interval/4)
+			defer cancel()
+			if err := tt.first.AcquireIdentify(ctx, 0, interval); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected context error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_ShardForGuildClusterMode(t *testing.T) {
+	c := New(context.Background(), WithShardIDs([]int{3, 1}, 4))
+	for _, id := range c.shardIDs {
+		c.shards = append(c.shards, newShard(id, 4, shardConfig{}))
+	}
+	c.totalShards = 4
+
+	// guild IDs whose shard is (id >> 22) % 4
+	if s := c.shardForGuild(Snowflake(1 << 22)); s == nil || s.shardID != 1 {
+		t.Fatalf("expected shard 1, got %v", s)
+	}
+	if s := c.shardForGuild(Snowflake(3 << 22)); s == nil || s.shardID != 3 {
+		t.Fatalf("expected shard 3, got %v", s)
+	}
+	if s := c.shardForGuild(Snowflake(2 << 22)); s != nil {
+		t.Fatalf("expected shard 2 to run in another process, got shard %d", s.shardID)
+	}
+	if err := c.Reshard(context.Background(), 8); !errors.Is(err, ErrClusterReshard) {
+		t.Fatalf("expected ErrClusterReshard, got %v", err)
+	}
+}
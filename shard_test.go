@@ -0,0 +1,809 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+// newFakeGateway starts a local Gateway calling serve for each shard connection.
+func newFakeGateway(t *testing.T, serve func(conn io.ReadWriter)) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serve(conn)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.Replace(srv.URL, "http://", "ws://", 1)
+}
+
+// newTestShard returns a shard connecting to url.
+func newTestShard(url string, onFatal func(int, error)) *Shard {
+	logger := NewDefaultLogger(io.Discard, LogLevelFatalLevel)
+	s := newShard(0, 1, shardConfig{
+		token:           "token",
+		logger:          logger,
+		dispatcher:      newDispatcher(logger, nil, NewDefaultCache(CacheFlagsNone)),
+		identifyLimiter: NewDefaultShardsRateLimiter(1, time.Second),
+		onFatal:         onFatal,
+	})
+	s.resumeURL = url
+	return s
+}
+
+func TestShard_FatalCloseCode(t *testing.T) {
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify
+			return
+		}
+		ws.WriteFrame(conn, ws.NewCloseFrame(ws.NewCloseFrameBody(
+			ws.StatusCode(GatewayCloseEventCodeAuthenticationFailed), "Authentication failed.")))
+	})
+
+	fatal := make(chan error, 1)
+	s := newTestShard(url, func(shardID int, err error) { fatal <- err })
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	select {
+	case err := <-fatal:
+		var closeErr *GatewayCloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != GatewayCloseEventCodeAuthenticationFailed {
+			t.Fatalf("expected GatewayCloseError 4004, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fatal close code not reported")
+	}
+
+	if err := s.send(context.Background(), []byte(`{}`), true); !errors.Is(err, ErrShardNotConnected) {
+		t.Fatalf("expected stopped shard, got %v", err)
+	}
+}
+
+func TestGatewayCloseEventCode_Reconnectable(t *testing.T) {
+	for code := GatewayCloseEventCode(4000); code <= 4014; code++ {
+		fatal := code == 4004 || code >= 4010
+		if code.Reconnectable() == fatal {
+			t.Errorf("code %d: expected reconnectable=%v", code, !fatal)
+		}
+	}
+	if !GatewayCloseEventCodeInvalidSeq.ResetsSession() || !GatewayCloseEventCodeSessionTimedOut.ResetsSession() {
+		t.Error("4007 and 4009 must reset the session")
+	}
+}
+
+func TestEventDeduplicator(t *testing.T) {
+	oldShards := []*Shard{{shardID: 0, totalShards: 1}}
+	newShards := []*Shard{{shardID: 0, totalShards: 2}, {shardID: 1, totalShards: 2}}
+	d := newEventDeduplicator(time.Minute, oldShards, newShards)
+
+	data := []byte(`{"guild_id":"4194304","user_id":"1","emoji":{"name":"👍"}}`) // guild on new shard 1
+	if d.duplicate(1, 0, 10, "MESSAGE_REACTION_ADD", data) {
+		t.Fatal("first event reported as duplicate")
+	}
+	if !d.duplicate(1, 0, 10, "MESSAGE_REACTION_ADD", data) {
+		t.Fatal("event replayed with the same sequence not reported as duplicate")
+	}
+	if d.duplicate(1, 0, 11, "MESSAGE_REACTION_ADD", data) {
+		t.Fatal("event repeated by the same shard reported as duplicate")
+	}
+	if d.duplicate(1, 0, 12, "MESSAGE_REACTION_REMOVE", data) {
+		t.Fatal("event with another name reported as duplicate")
+	}
+
+	// the new shard receives both reactions, each pairs with one of the old shard
+	for seq := range int64(2) {
+		if !d.duplicate(2, 1, 5+seq, "MESSAGE_REACTION_ADD", data) {
+			t.Fatalf("event %d of the other shard set not reported as duplicate", seq)
+		}
+	}
+	if d.duplicate(2, 1, 7, "MESSAGE_REACTION_ADD", data) {
+		t.Fatal("event without a pair in the other shard set reported as duplicate")
+	}
+}
+
+func TestEventDeduplicator_GuildNotServedByBothSets(t *testing.T) {
+	// a cluster running the shard 0 of 2 only serves half of the guilds
+	oldShards := []*Shard{{shardID: 0, totalShards: 1}}
+	newShards := []*Shard{{shardID: 0, totalShards: 2}}
+	d := newEventDeduplicator(time.Minute, oldShards, newShards)
+
+	served := []byte(`{"guild_id":"8388608","content":"hi"}`)   // new shard 0
+	unserved := []byte(`{"guild_id":"4194304","content":"hi"}`) // new shard 1
+	if d.duplicate(1, 0, 1, "MESSAGE_CREATE", served) || !d.duplicate(2, 0, 1, "MESSAGE_CREATE", served) {
+		t.Fatal("expected the event of a guild served by both sets to be deduplicated")
+	}
+	if d.duplicate(1, 0, 2, "MESSAGE_CREATE", unserved) || d.duplicate(1, 0, 3, "MESSAGE_CREATE", unserved) {
+		t.Fatal("expected the events of a guild not served by both sets to be dispatched")
+	}
+
+	guild := []byte(`{"id":"4194304","name":"goda"}`)
+	if eventGuildID("GUILD_UPDATE", guild) != 4194304 {
+		t.Fatal("expected the guild of GUILD_UPDATE to be its id")
+	}
+}
+
+func TestDefaultShardsRateLimiter_Buckets(t *testing.T) {
+	const interval = 200 * time.Millisecond
+	rl := NewDefaultShardsRateLimiter(16, interval)
+	ctx := context.Background()
+
+	start := time.Now()
+	for shardID := range 16 {
+		if err := rl.Wait(ctx, shardID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= interval {
+		t.Fatalf("16 buckets identified in %v, expected in parallel", elapsed)
+	}
+
+	// shard 16 shares the bucket of shard 0
+	if err := rl.Wait(ctx, 16); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Fatalf("shard 16 identified after %v, expected to wait for its bucket", elapsed)
+	}
+}
+
+func TestSessionStartBudget_WaitsForReset(t *testing.T) {
+	logger := NewDefaultLogger(io.Discard, LogLevelFatalLevel)
+	refreshed := 0
+	b := newSessionStartBudget(SessionStartLimit{Total: 2, Remaining: 1, ResetAfter: 200}, func() (SessionStartLimit, error) {
+		refreshed++
+		return SessionStartLimit{Total: 2, Remaining: 0, ResetAfter: 200}, nil
+	}, logger)
+	ctx := context.Background()
+
+	start := time.Now()
+	if err := b.take(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.snapshot().Remaining; got != 0 {
+		t.Fatalf("expected 0 remaining, got %d", got)
+	}
+
+	short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := b.take(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected exhausted budget to wait, got %v", err)
+	}
+	if refreshed != 1 {
+		t.Fatalf("expected exhausted budget to refresh once, refreshed %d", refreshed)
+	}
+
+	if err := b.take(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("identify allowed after %v, before the limit reset", elapsed)
+	}
+	if got := b.snapshot().Remaining; got != 1 {
+		t.Fatalf("expected reset budget with 1 remaining, got %d", got)
+	}
+}
+
+func TestShard_GuildAvailability(t *testing.T) {
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify
+			return
+		}
+		for _, msg := range []string{
+			`{"op":0,"s":1,"t":"READY","d":{"session_id":"s","guilds":[{"id":"1","unavailable":true},{"id":"2","unavailable":true}]}}`,
+			`{"op":0,"s":2,"t":"GUILD_CREATE","d":{"id":"1"}}`,
+			`{"op":0,"s":3,"t":"GUILD_CREATE","d":{"id":"3"}}`,
+			`{"op":0,"s":4,"t":"GUILD_CREATE","d":{"id":"2"}}`,
+		} {
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(msg))
+		}
+		wsutil.ReadClientData(conn) // wait for the shard to close
+	})
+
+	s := newTestShard(url, nil)
+	ready := make(chan []Snowflake, 1)
+	s.onReady = func(shardID int, unavailable []Snowflake) { ready <- unavailable }
+	joined := make(chan GuildCreateEvent, 3)
+	s.dispatcher.OnGuildCreate(func(evt GuildCreateEvent) { joined <- evt })
+
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer s.Shutdown()
+
+	select {
+	case unavailable := <-ready:
+		if len(unavailable) != 0 {
+			t.Fatalf("expected every guild available, got %v", unavailable)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("shard not ready")
+	}
+
+	want := map[Snowflake]bool{1: false, 2: false, 3: true}
+	for range want {
+		select {
+		case evt := <-joined:
+			if evt.Joined != want[evt.Guild.ID] {
+				t.Errorf("guild %d: expected joined=%v", evt.Guild.ID, want[evt.Guild.ID])
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("missing GUILD_CREATE")
+		}
+	}
+}
+
+func TestShard_ReadyTimeout(t *testing.T) {
+	s := newTestShard("", nil)
+	s.readyTimeout = 50 * time.Millisecond
+	ready := make(chan []Snowflake, 1)
+	s.onReady = func(shardID int, unavailable []Snowflake) { ready <- unavailable }
+
+	s.trackReadyGuilds([]byte(`{"guilds":[{"id":"1"},{"id":"2"}]}`))
+	if s.guildCreated([]byte(`{"id":"1"}`)) {
+		t.Fatal("guild of READY reported as joined")
+	}
+
+	select {
+	case unavailable := <-ready:
+		if len(unavailable) != 1 || unavailable[0] != 2 {
+			t.Fatalf("expected guild 2 unavailable, got %v", unavailable)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("shard not ready after timeout")
+	}
+}
+
+func TestShard_LatencyAndStatus(t *testing.T) {
+	const delay = 50 * time.Millisecond
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify
+			return
+		}
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":1,"t":"READY","d":{"session_id":"s","guilds":[]}}`))
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":1,"d":null}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // heartbeat
+			return
+		}
+		time.Sleep(delay)
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":11}`))
+		wsutil.ReadClientData(conn) // wait for the shard to close
+	})
+
+	s := newTestShard(url, nil)
+	if s.Status() != ShardStatusDisconnected {
+		t.Fatalf("expected new shard disconnected, got %s", s.Status())
+	}
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Latency() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if latency := time.Duration(s.Latency()) * time.Millisecond; latency < delay || latency > time.Second {
+		t.Fatalf("expected latency around %v, got %v", delay, latency)
+	}
+	if s.Status() != ShardStatusReady {
+		t.Fatalf("expected ready shard, got %s", s.Status())
+	}
+
+	s.Shutdown()
+	if s.Status() != ShardStatusDisconnected {
+		t.Fatalf("expected disconnected shard after shutdown, got %s", s.Status())
+	}
+	if s.Reconnects() != 0 || s.Resumes() != 0 || s.InvalidSessions() != 0 {
+		t.Fatal("expected zero counters")
+	}
+}
+
+func TestShard_LifecycleEvents(t *testing.T) {
+	var conns atomic.Int32
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify
+			return
+		}
+		if conns.Add(1) == 1 {
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":7,"d":null}`))
+		} else {
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":9,"d":true}`))
+			if _, _, err := wsutil.ReadClientData(conn); err != nil { // resume
+				return
+			}
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":2,"t":"RESUMED","d":{}}`))
+		}
+		wsutil.ReadClientData(conn) // wait for the shard to close
+	})
+
+	s := newTestShard(url, nil)
+	events := make(chan string, 16)
+	s.dispatcher.OnShardConnect(func(ShardConnectEvent) { events <- "connect" })
+	s.dispatcher.OnShardDisconnect(func(evt ShardDisconnectEvent) {
+		if errors.Is(evt.Err, ErrReconnectRequested) {
+			events <- "disconnect"
+		}
+	})
+	s.dispatcher.OnShardReconnecting(func(evt ShardReconnectingEvent) { events <- "reconnecting" })
+	s.dispatcher.OnInvalidSession(func(evt InvalidSessionEvent) {
+		if evt.Resumable {
+			events <- "invalid"
+		}
+	})
+	s.dispatcher.OnShardResumed(func(ShardResumedEvent) { events <- "resumed" })
+
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer s.Shutdown()
+
+	for _, want := range []string{"connect", "disconnect", "reconnecting", "connect", "invalid", "resumed"} {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Fatalf("expected %s event, got %s", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("missing %s event", want)
+		}
+	}
+	if s.Reconnects() != 1 || s.InvalidSessions() != 1 || s.Resumes() != 1 {
+		t.Fatalf("unexpected counters: reconnects=%d invalid=%d resumes=%d", s.Reconnects(), s.InvalidSessions(), s.Resumes())
+	}
+}
+
+func TestGatewaySendQueue_LimitAndPriority(t *testing.T) {
+	var written []string
+	q := newGatewaySendQueue(func(payload []byte) error {
+		written = append(written, string(payload))
+		return nil
+	})
+	// normal payloads leave gatewayReservedSends for priority ones
+	q.limiter = newGatewaySendLimiter(gatewayReservedSends+2, 200*time.Millisecond)
+	defer q.close()
+	ctx := context.Background()
+
+	for _, payload := range []string{"a", "b"} {
+		if err := q.send(ctx, []byte(payload), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now()
+	normal := make(chan error, 1)
+	go func() { normal <- q.send(ctx, []byte("c"), false) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// the heartbeat jumps ahead of the rate limited payload
+	if err := q.send(ctx, []byte("heartbeat"), true); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("priority payload waited %v", elapsed)
+	}
+
+	if err := <-normal; err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("rate limited payload sent after %v, before the window reset", elapsed)
+	}
+
+	want := []string{"a", "b", "heartbeat", "c"}
+	if strings.Join(written, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected writes %v, got %v", want, written)
+	}
+
+	short, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := q.send(short, []byte("late"), false); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled send, got %v", err)
+	}
+}
+
+func TestShard_SendQueuePerConnection(t *testing.T) {
+	// identify and the normal payloads use the whole budget of the first connection
+	budget := gatewaySendLimit - gatewayReservedSends - 1
+
+	var conns atomic.Int32
+	var url string
+	closeFirst := make(chan struct{})
+	resumed := make(chan []byte, 1)
+	afterResume := make(chan []byte, 1)
+	url = newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		if conns.Add(1) == 1 {
+			if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify
+				return
+			}
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":1,"t":"READY","d":{"session_id":"s1","resume_gateway_url":"`+url+`","guilds":[]}}`))
+			for range budget {
+				if _, _, err := wsutil.ReadClientData(conn); err != nil {
+					return
+				}
+			}
+			<-closeFirst
+			return
+		}
+
+		msg, _, err := wsutil.ReadClientData(conn)
+		if err != nil {
+			return
+		}
+		resumed <- msg
+		if msg, _, err = wsutil.ReadClientData(conn); err == nil {
+			afterResume <- msg
+		}
+		io.Copy(io.Discard, conn)
+	})
+
+	s := newTestShard(url, nil)
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer s.Shutdown()
+	select {
+	case <-s.readyCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("READY not handled")
+	}
+
+	ctx := context.Background()
+	for range budget {
+		if err := s.sendLimited(ctx, []byte(`{"op":3,"d":{}}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the budget of the connection is used, this payload waits in its queue
+	stale := make(chan error, 1)
+	go func() { stale <- s.sendLimited(ctx, []byte(`{"op":3,"d":"stale"}`)) }()
+	time.Sleep(50 * time.Millisecond)
+	close(closeFirst)
+
+	select {
+	case err := <-stale:
+		if !errors.Is(err, ErrShardNotConnected) {
+			t.Fatalf("expected the queued payload to fail with its connection, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued payload not dropped with its connection")
+	}
+
+	select {
+	case msg := <-resumed:
+		if !strings.Contains(string(msg), `"op":6`) {
+			t.Fatalf("expected RESUME first on the new connection, got %s", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("shard did not resume")
+	}
+
+	// the new connection starts with a full budget
+	short, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := s.sendLimited(short, []byte(`{"op":3,"d":"fresh"}`)); err != nil {
+		t.Fatalf("expected the send budget to be reset on connect, got %v", err)
+	}
+	if msg := <-afterResume; !strings.Contains(string(msg), "fresh") {
+		t.Fatalf("expected the fresh payload after RESUME, got %s", msg)
+	}
+}
+
+func TestClient_SetPresence(t *testing.T) {
+	c := New(context.Background())
+	defer c.Shutdown()
+	shard := newTestShard("", nil)
+	defer shard.Shutdown()
+	c.shards, c.totalShards = []*Shard{shard}, 1
+
+	// shards created while resharding read the presence concurrently
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			c.shardConfig()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 100 {
+		if err := c.SetPresence(ctx, StatusTypeIdle); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the context error, got %v", err)
+		}
+	}
+	<-done
+
+	if p := c.shardConfig().presence; p == nil || p.Status != StatusTypeIdle {
+		t.Fatalf("expected new shards to identify with the presence, got %+v", p)
+	}
+	if p := shard.presence.Load(); p == nil || p.Status != StatusTypeIdle {
+		t.Fatalf("expected the shard to keep the presence, got %+v", p)
+	}
+}
+
+// goroutines returns the stacks of the running goroutines by goroutine ID.
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[string]string)
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		header, _, _ := strings.Cut(stack, "\n")
+		id := strings.Fields(header)
+		if len(id) > 1 {
+			stacks[id[1]] = stack
+		}
+	}
+	return stacks
+}
+
+// leakedGoroutines returns the stacks of goda goroutines started since before,
+// waiting up to timeout for them to exit.
+func leakedGoroutines(before map[string]string, timeout time.Duration) []string {
+	deadline := time.Now().Add(timeout)
+	for {
+		var leaked []string
+		for id, stack := range goroutines() {
+			if _, ok := before[id]; ok || !strings.Contains(stack, "ra7eemi/goda.") ||
+				strings.Contains(stack, "testing.tRunner") {
+				continue
+			}
+			leaked = append(leaked, stack)
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClient_ShutdownLeavesNoGoroutines(t *testing.T) {
+	before := goroutines()
+
+	var url string
+	var conns atomic.Int32
+	url = newFakeGateway(t, func(conn io.ReadWriter) {
+		first := conns.Add(1) == 1
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":50}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify or resume
+			return
+		}
+		if first {
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":1,"t":"READY","d":{"session_id":"abc","resume_gateway_url":"`+url+`","guilds":[]}}`))
+		} else {
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":2,"t":"RESUMED","d":null}`))
+		}
+
+		for heartbeats := 0; ; heartbeats++ {
+			if first && heartbeats == 2 {
+				// the old heartbeat must stop with the connection
+				wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":7,"d":null}`))
+			}
+			if _, _, err := wsutil.ReadClientData(conn); err != nil {
+				return
+			}
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":11}`))
+		}
+	})
+
+	c := New(context.Background(),
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+	)
+	c.identifyLimiter = NewDefaultShardsRateLimiter(1, time.Second)
+	s := newShard(0, 1, c.shardConfig())
+	s.resumeURL = url
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	c.shards = append(c.shards, s)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Resumes() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("shard did not resume after RECONNECT")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	c.Shutdown()
+	if s.Status() != ShardStatusDisconnected {
+		t.Fatalf("expected disconnected shard, got %s", s.Status())
+	}
+	if leaked := leakedGoroutines(before, 2*time.Second); len(leaked) > 0 {
+		t.Fatalf("%d goroutines left after Shutdown:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+	}
+}
+
+func TestClient_ShutdownLeavesNoGoroutines_FailedStart(t *testing.T) {
+	before := goroutines()
+
+	var conns atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v10/gateway/bot" {
+			w.Write([]byte(`{"url":"ws://discord.invalid","shards":2,"session_start_limit":` +
+				`{"total":1000,"remaining":1000,"reset_after":0,"max_concurrency":16}}`))
+			return
+		}
+		if conns.Add(1) > 1 {
+			// the second shard fails to connect
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":50}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil {
+			return
+		}
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":1,"t":"READY","d":{"session_id":"abc","guilds":[]}}`))
+		for {
+			if _, _, err := wsutil.ReadClientData(conn); err != nil {
+				return
+			}
+			wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":11}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(context.Background(),
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+		WithRestURL(srv.URL+"/api/v10"),
+		WithGatewayURL(strings.Replace(srv.URL, "http://", "ws://", 1)),
+	)
+	if err := c.Start(); err == nil {
+		t.Fatal("expected Start to fail when a shard fails to connect")
+	}
+	if n := conns.Load(); n != 2 {
+		t.Fatalf("expected both shards to connect, got %d connections", n)
+	}
+	if leaked := leakedGoroutines(before, 2*time.Second); len(leaked) > 0 {
+		t.Fatalf("%d goroutines left after a failed Start:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+	}
+}
+
+func TestClient_ChunkGuildsOneAtATimePerShard(t *testing.T) {
+	var pending, maxPending, answered atomic.Int32
+	url := newFakeGateway(t, func(conn io.ReadWriter) {
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify
+			return
+		}
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":1,"t":"READY","d":{"session_id":"abc","guilds":[]}}`))
+
+		requests := make(chan []byte, 8)
+		go func() {
+			// answer each request after a while, like Discord chunking a large guild
+			for msg := range requests {
+				var req struct {
+					D struct {
+						GuildID Snowflake `json:"guild_id"`
+						Nonce   string    `json:"nonce"`
+					} `json:"d"`
+				}
+				json.Unmarshal(msg, &req)
+				time.Sleep(30 * time.Millisecond)
+				pending.Add(-1)
+				answered.Add(1)
+				wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":2,"t":"GUILD_MEMBERS_CHUNK","d":{"guild_id":"`+
+					req.D.GuildID.String()+`","members":[],"chunk_index":0,"chunk_count":1,"nonce":"`+req.D.Nonce+`"}}`))
+			}
+		}()
+		defer close(requests)
+		for {
+			msg, _, err := wsutil.ReadClientData(conn)
+			if err != nil {
+				return
+			}
+			if strings.Contains(string(msg), `"op":8`) {
+				if n := pending.Add(1); n > maxPending.Load() {
+					maxPending.Store(n)
+				}
+				requests <- msg
+			}
+		}
+	})
+
+	c := New(context.Background(),
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+	)
+	defer c.Shutdown()
+	c.identifyLimiter = NewDefaultShardsRateLimiter(1, time.Second)
+	s := newShard(0, 1, c.shardConfig())
+	s.resumeURL = url
+	if err := s.connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	c.shards, c.totalShards = []*Shard{s}, 1
+	if err := s.waitReady(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for id := range 3 {
+		c.chunkGuild(GuildCreateEvent{Guild: GatewayGuild{
+			RestGuild:   RestGuild{Guild: Guild{ID: Snowflake(id + 1)}},
+			Large:       true,
+			MemberCount: 1000,
+		}})
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for answered.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 guilds chunked, got %d", answered.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := maxPending.Load(); n != 1 {
+		t.Fatalf("expected the guilds of a shard to be chunked one at a time, got %d concurrent requests", n)
+	}
+}
+
+func TestClient_StartCancelledWhileWaitingSessionStartLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"url":"ws://discord.invalid","shards":1,"session_start_limit":` +
+			`{"total":1000,"remaining":0,"reset_after":3600000,"max_concurrency":1}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := New(ctx,
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+		WithRestURL(srv.URL+"/api/v10"),
+		WithSessionStartLimitWait(),
+	)
+	defer c.Shutdown()
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	if err := c.Start(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Start to return the context error, got %v", err)
+	}
+}
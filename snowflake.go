@@ -55,6 +55,17 @@ func (s *Snowflake) UnmarshalJSON(buf []byte) error {
 		return nil
 	}
 
+	// Snowflakes sent as integers over the ETF Gateway encoding end up
+	// unquoted in the json.RawMessage fields transcoded from ETF
+	if len(buf) > 0 && buf[0] >= '0' && buf[0] <= '9' {
+		id, err := strconv.ParseUint(BytesToString(buf), 10, 64)
+		if err != nil {
+			return err
+		}
+		*s = Snowflake(id)
+		return nil
+	}
+
 	// Fallback: handle edge cases with standard library
 	str, err := strconv.Unquote(string(buf))
 	if err != nil {
@@ -70,6 +81,18 @@ func (s *Snowflake) UnmarshalJSON(buf []byte) error {
 	return nil
 }
 
+var _ etfUnmarshaler = (*Snowflake)(nil)
+
+// unmarshalETF decodes a snowflake sent as an integer or a decimal binary.
+func (s *Snowflake) unmarshalETF(d *etfDecoder) error {
+	id, err := d.uintOrText()
+	if err != nil {
+		return err
+	}
+	*s = Snowflake(id)
+	return nil
+}
+
 func (s Snowflake) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + strconv.FormatUint(uint64(s), 10) + `"`), nil
 }
@@ -0,0 +1,95 @@
+//go:build ignore
+
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+// gen_zstd_stream compresses a Gateway recording the way Discord's zstd-stream
+// transport compression does: a single zstd frame for the whole connection,
+// flushed after each payload. It uses the reference zstd library through cgo.
+//
+// The recording holds one raw Gateway payload per line, the output holds each
+// compressed message prefixed with its little-endian uint32 length:
+//
+//	go run testdata/gen_zstd_stream.go recording.txt testdata/zstd_stream.bin
+//
+// testdata/zstd_stream.bin holds the synthetic recording of the decompressor
+// benchmark, written one payload per line, compressed at the default level.
+package main
+
+/*
+#cgo LDFLAGS: -lzstd
+#include <stdlib.h>
+#include <zstd.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"log"
+	"os"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatal("usage: go run gen_zstd_stream.go <recording> <output>")
+	}
+
+	in, err := os.Open(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer in.Close()
+
+	cctx := C.ZSTD_createCCtx()
+	defer C.ZSTD_freeCCtx(cctx)
+
+	var out bytes.Buffer
+	dstSize := C.ZSTD_CStreamOutSize()
+	dst := C.malloc(dstSize)
+	defer C.free(dst)
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 0, 1<<20), 64<<20)
+	for sc.Scan() {
+		payload := bytes.TrimSpace(sc.Bytes())
+		if len(payload) == 0 {
+			continue
+		}
+
+		var msg []byte
+		input := C.ZSTD_inBuffer{src: C.CBytes(payload), size: C.size_t(len(payload))}
+		for {
+			output := C.ZSTD_outBuffer{dst: dst, size: dstSize}
+			left := C.ZSTD_compressStream2(cctx, &output, &input, C.ZSTD_e_flush)
+			if C.ZSTD_isError(left) != 0 {
+				log.Fatal(C.GoString(C.ZSTD_getErrorName(left)))
+			}
+			msg = append(msg, C.GoBytes(dst, C.int(output.pos))...)
+			if left == 0 {
+				break
+			}
+		}
+		C.free(input.src)
+
+		out.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(msg))))
+		out.Write(msg)
+	}
+	if err := sc.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(os.Args[2], out.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,156 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gobwas/ws"
+)
+
+/*****************************
+ *        Transport
+ *****************************/
+
+// defaultDialTimeout is the time allowed to establish a TCP connection when none is configured.
+const defaultDialTimeout = 30 * time.Second
+
+// transportConfig holds the network settings shared by the REST client and the Gateway shards.
+type transportConfig struct {
+	tlsConfig   *tls.Config   // TLS settings of https and wss connections, nil uses the defaults
+	proxy       *url.URL      // HTTP proxy, nil uses the proxy of the environment for REST and none for the Gateway
+	dialTimeout time.Duration // TCP connect timeout, 0 uses defaultDialTimeout
+}
+
+// timeout returns the TCP connect timeout.
+func (t transportConfig) timeout() time.Duration {
+	if t.dialTimeout > 0 {
+		return t.dialTimeout
+	}
+	return defaultDialTimeout
+}
+
+// httpClient returns the HTTP client used by the REST API.
+func (t transportConfig) httpClient() *http.Client {
+	proxy := http.ProxyFromEnvironment
+	if t.proxy != nil {
+		proxy = http.ProxyURL(t.proxy)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   t.timeout(),
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           proxy,
+			DialContext:     dialer.DialContext,
+			TLSClientConfig: t.tlsConfig,
+
+			MaxIdleConns:        500,
+			MaxIdleConnsPerHost: 100,
+			MaxConnsPerHost:     200,
+
+			IdleConnTimeout:       120 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+
+			DisableKeepAlives: false,
+			ForceAttemptHTTP2: true,
+		},
+	}
+}
+
+// wsDialer returns the WebSocket dialer used by the Gateway shards.
+func (t transportConfig) wsDialer() ws.Dialer {
+	return ws.Dialer{
+		Timeout:   t.dialTimeout,
+		TLSConfig: t.tlsConfig,
+		NetDial:   t.dial,
+	}
+}
+
+// dial opens a TCP connection to addr, tunneled through the proxy if one is configured.
+func (t transportConfig) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: t.timeout()}
+	if t.proxy == nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	proxyAddr := t.proxy.Host
+	if t.proxy.Port() == "" {
+		proxyAddr = net.JoinHostPort(t.proxy.Hostname(), "80")
+		if t.proxy.Scheme == "https" {
+			proxyAddr = net.JoinHostPort(t.proxy.Hostname(), "443")
+		}
+	}
+	conn, err := dialer.DialContext(ctx, network, proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if t.proxy.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: t.proxy.Hostname()})
+	}
+
+	if err := proxyConnect(ctx, conn, t.proxy, addr); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// proxyConnect asks the HTTP proxy on conn to open a tunnel to addr.
+func proxyConnect(ctx context.Context, conn net.Conn, proxy *url.URL, addr string) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxy.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+
+	// TLS and WebSocket clients speak first, so nothing past the response is buffered
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return errors.New("proxy CONNECT " + addr + ": " + resp.Status)
+	}
+	// the body of a successful CONNECT is the tunnel itself, it is not read
+	return nil
+}
@@ -0,0 +1,146 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+// newMockDiscord starts a local server answering /api/v10/gateway/bot
+// and serving the Gateway on every other path.
+func newMockDiscord(t *testing.T) (restURL, gatewayURL string) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v10/gateway/bot" {
+			w.Write([]byte(`{"url":"ws://discord.invalid","shards":1,"session_start_limit":` +
+				`{"total":1000,"remaining":1000,"reset_after":0,"max_concurrency":1}}`))
+			return
+		}
+
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":10,"d":{"heartbeat_interval":45000}}`))
+		if _, _, err := wsutil.ReadClientData(conn); err != nil { // identify
+			return
+		}
+		wsutil.WriteServerMessage(conn, ws.OpText, []byte(`{"op":0,"s":1,"t":"READY","d":`+
+			`{"session_id":"abc","resume_gateway_url":"ws://discord.invalid","guilds":[]}}`))
+		for {
+			if _, _, err := wsutil.ReadClientData(conn); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL + "/api/v10", strings.Replace(srv.URL, "http://", "ws://", 1)
+}
+
+func TestClient_CustomURLs(t *testing.T) {
+	restURL, gatewayURL := newMockDiscord(t)
+
+	c := New(context.Background(),
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+		WithRestURL(restURL),
+		WithGatewayURL(gatewayURL),
+		WithDialTimeout(2*time.Second),
+	)
+	ready := make(chan struct{})
+	c.OnClientReady(func(ClientReadyEvent) { close(ready) })
+
+	started := make(chan error, 1)
+	go func() { started <- c.Start() }()
+
+	select {
+	case <-ready:
+	case err := <-started:
+		t.Fatalf("Start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("client not ready through the mock Discord")
+	}
+
+	c.Shutdown()
+	if err := <-started; err != nil {
+		t.Fatalf("Start after Shutdown: %v", err)
+	}
+}
+
+func TestTransportConfig_ProxyTunnel(t *testing.T) {
+	_, gatewayURL := newMockDiscord(t)
+
+	var tunnels atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer target.Close()
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tunnels.Add(1)
+		go func() {
+			io.Copy(target, conn)
+			target.Close()
+		}()
+		io.Copy(conn, target)
+	}))
+	t.Cleanup(proxy.Close)
+
+	c := New(context.Background(),
+		WithToken(strings.Repeat("t", 60)),
+		WithLogger(NewDefaultLogger(io.Discard, LogLevelFatalLevel)),
+		WithProxy(strings.Replace(proxy.URL, "http://", "http://user:password@", 1)),
+	)
+	defer c.Shutdown()
+
+	conn, br, _, err := c.transport.wsDialer().Dial(context.Background(), gatewayURL)
+	if err != nil {
+		t.Fatalf("dial through proxy: %v", err)
+	}
+	defer conn.Close()
+	if br != nil {
+		conn = &bufferedConn{Conn: conn, r: io.MultiReader(br, conn)}
+	}
+
+	msg, err := wsutil.ReadServerText(conn)
+	if err != nil || !strings.Contains(string(msg), `"op":10`) {
+		t.Fatalf("expected Hello through the tunnel, got %q, %v", msg, err)
+	}
+	if tunnels.Load() != 1 {
+		t.Fatalf("expected 1 proxy tunnel, got %d", tunnels.Load())
+	}
+}
@@ -0,0 +1,604 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+/*****************************
+ *   Voice Gateway Protocol
+ *****************************/
+
+// voiceGatewayVersion is the version of the voice Gateway protocol used.
+const voiceGatewayVersion = "8"
+
+// voiceOpcode represents the operation codes used in voice Gateway WebSocket frames.
+//
+// Reference: https://discord.com/developers/docs/topics/opcodes-and-status-codes#voice-voice-opcodes
+type voiceOpcode int
+
+const (
+	voiceOpcodeIdentify           voiceOpcode = 0
+	voiceOpcodeSelectProtocol     voiceOpcode = 1
+	voiceOpcodeReady              voiceOpcode = 2
+	voiceOpcodeHeartbeat          voiceOpcode = 3
+	voiceOpcodeSessionDescription voiceOpcode = 4
+	voiceOpcodeSpeaking           voiceOpcode = 5
+	voiceOpcodeHeartbeatACK       voiceOpcode = 6
+	voiceOpcodeHello              voiceOpcode = 8
+)
+
+// voicePayload represents a voice Gateway payload.
+type voicePayload struct {
+	Op  voiceOpcode     `json:"op"`
+	D   json.RawMessage `json:"d"`
+	Seq *int64          `json:"seq,omitempty"` // sequence number of server messages
+}
+
+// VoiceEncryptionMode represents the encryption of the voice packets sent over UDP.
+//
+// Reference: https://discord.com/developers/docs/topics/voice-connections#transport-encryption-modes
+type VoiceEncryptionMode string
+
+const (
+	// VoiceEncryptionModeAES256GCM is AEAD AES256-GCM, preferred when available.
+	VoiceEncryptionModeAES256GCM VoiceEncryptionMode = "aead_aes256_gcm_rtpsize"
+	// VoiceEncryptionModeXChaCha20Poly1305 is AEAD XChaCha20-Poly1305, always available.
+	VoiceEncryptionModeXChaCha20Poly1305 VoiceEncryptionMode = "aead_xchacha20_poly1305_rtpsize"
+)
+
+// Is checks if the encryption mode matches the provided mode.
+func (m VoiceEncryptionMode) Is(mode VoiceEncryptionMode) bool {
+	return m == mode
+}
+
+// VoiceSpeakingFlags represents how the bot is speaking.
+//
+// Reference: https://discord.com/developers/docs/topics/voice-connections#speaking
+type VoiceSpeakingFlags int
+
+const (
+	// VoiceSpeakingFlagMicrophone is normal transmission of voice audio.
+	VoiceSpeakingFlagMicrophone VoiceSpeakingFlags = 1 << iota
+	// VoiceSpeakingFlagSoundshare is transmission of context audio for video, no speaking indicator.
+	VoiceSpeakingFlagSoundshare
+	// VoiceSpeakingFlagPriority is priority speaker, lowering audio of other speakers.
+	VoiceSpeakingFlagPriority
+)
+
+// Has returns true if all provided flags are set.
+func (f VoiceSpeakingFlags) Has(flags ...VoiceSpeakingFlags) bool {
+	return BitFieldHas(f, flags...)
+}
+
+// OpusReader is a source of Opus packets, each packet holding 20ms of
+// 48kHz stereo audio.
+//
+// ReadOpus returns io.EOF once the source has no more packets.
+type OpusReader interface {
+	ReadOpus() ([]byte, error)
+}
+
+const (
+	// opusFrameDuration is the duration of the audio held by a single Opus packet.
+	opusFrameDuration = 20 * time.Millisecond
+	// opusFrameSamples is the number of samples per channel in a single Opus packet.
+	opusFrameSamples = 960
+	// rtpHeaderSize is the size of the RTP header of voice packets.
+	rtpHeaderSize = 12
+)
+
+// opusSilenceFrame is sent a few times after the audio to avoid interpolation glitches.
+var opusSilenceFrame = []byte{0xF8, 0xFF, 0xFE}
+
+var (
+	errVoiceUnexpectedPayload = errors.New("goda: unexpected voice gateway payload")
+	errVoiceNoEncryptionMode  = errors.New("goda: voice server offers no supported encryption mode")
+	errVoiceIPDiscovery       = errors.New("goda: invalid voice ip discovery response")
+)
+
+/*****************************
+ *   VoiceConnection
+ *****************************/
+
+// VoiceConnection is a connection to a Discord voice server,
+// used to send Opus audio to a voice channel.
+//
+// It speaks the voice Gateway protocol over a WebSocket and sends encrypted
+// RTP packets over UDP.
+//
+// Notes:
+//   - Sending audio only, received audio is ignored.
+//   - DAVE end-to-end encryption is not supported, voice servers requiring it
+//     close the connection.
+//   - The connection is not resumed, open a new one after it is closed.
+type VoiceConnection struct {
+	session VoiceSession
+	logger  Logger
+	dialer  ws.Dialer
+
+	writeMu sync.Mutex // serializes writes on conn
+	conn    net.Conn   // voice Gateway websocket connection
+	udp     net.Conn   // voice UDP connection
+
+	ssrc uint32              // RTP synchronization source of the bot
+	mode VoiceEncryptionMode // selected encryption mode
+	aead cipher.AEAD         // cipher of the selected encryption mode
+
+	sendMu    sync.Mutex // serializes RTP packets
+	sequence  uint16     // RTP sequence number
+	timestamp uint32     // RTP timestamp
+	nonce     uint32     // AEAD nonce counter
+	packet    []byte     // reusable RTP packet buffer
+
+	seq     atomic.Int64 // last received voice Gateway sequence number
+	latency atomic.Int64 // heartbeat latency in milliseconds
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewVoiceConnection creates a voice connection for the given session,
+// as returned by Client.JoinVoiceChannel. Call Open to connect it.
+//
+// A nil logger discards all logs.
+func NewVoiceConnection(session VoiceSession, logger Logger) *VoiceConnection {
+	if logger == nil {
+		logger = NewDefaultLogger(io.Discard, LogLevelFatalLevel)
+	}
+	return &VoiceConnection{
+		session: session,
+		logger:  logger,
+		closed:  make(chan struct{}),
+	}
+}
+
+// voiceGatewayURL returns the voice Gateway URL of a voice server endpoint.
+func voiceGatewayURL(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "wss://" + endpoint
+	}
+	return strings.TrimSuffix(endpoint, "/") + "/?v=" + voiceGatewayVersion
+}
+
+// Open connects to the voice server and performs the voice handshake:
+// identify, UDP IP discovery, protocol selection and session description.
+//
+// The handshake is bounded by the deadline of ctx.
+//
+// Usage example:
+//
+//	vc := goda.NewVoiceConnection(session, client.Logger)
+//	if err := vc.Open(ctx); err != nil {
+//	    return err
+//	}
+//	defer vc.Close()
+//
+// Returns:
+//   - error: a dial, protocol or encryption error.
+func (v *VoiceConnection) Open(ctx context.Context) error {
+	conn, br, _, err := v.dialer.Dial(ctx, voiceGatewayURL(v.session.Endpoint))
+	if err != nil {
+		return err
+	}
+	if br != nil {
+		// the server sent Hello along with the handshake response
+		conn = &bufferedConn{Conn: conn, r: io.MultiReader(br, conn)}
+	}
+	v.conn = conn
+	v.seq.Store(-1)
+
+	if err := v.handshake(ctx); err != nil {
+		v.Close()
+		return err
+	}
+
+	v.logger.Info("Voice connection to guild " + v.session.GuildID.String() + " opened")
+	go v.readLoop()
+	return nil
+}
+
+// handshake runs the voice handshake on the freshly dialed connection.
+func (v *VoiceConnection) handshake(ctx context.Context) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		v.conn.SetDeadline(deadline)
+		defer v.conn.SetDeadline(time.Time{})
+	}
+
+	hello, err := v.waitFor(voiceOpcodeHello)
+	if err != nil {
+		return err
+	}
+	var helloData struct {
+		HeartbeatInterval float64 `json:"heartbeat_interval"`
+	}
+	if err := json.Unmarshal(hello, &helloData); err != nil {
+		return err
+	}
+	go v.heartbeat(time.Duration(helloData.HeartbeatInterval * float64(time.Millisecond)))
+
+	err = v.send(voiceOpcodeIdentify, map[string]any{
+		"server_id":                 v.session.GuildID,
+		"user_id":                   v.session.UserID,
+		"session_id":                v.session.SessionID,
+		"token":                     v.session.Token,
+		"max_dave_protocol_version": 0,
+	})
+	if err != nil {
+		return err
+	}
+
+	ready, err := v.waitFor(voiceOpcodeReady)
+	if err != nil {
+		return err
+	}
+	var readyData struct {
+		SSRC  uint32                `json:"ssrc"`
+		IP    string                `json:"ip"`
+		Port  int                   `json:"port"`
+		Modes []VoiceEncryptionMode `json:"modes"`
+	}
+	if err := json.Unmarshal(ready, &readyData); err != nil {
+		return err
+	}
+	v.ssrc = readyData.SSRC
+
+	switch {
+	case slices.Contains(readyData.Modes, VoiceEncryptionModeAES256GCM):
+		v.mode = VoiceEncryptionModeAES256GCM
+	case slices.Contains(readyData.Modes, VoiceEncryptionModeXChaCha20Poly1305):
+		v.mode = VoiceEncryptionModeXChaCha20Poly1305
+	default:
+		return errVoiceNoEncryptionMode
+	}
+
+	dialer := net.Dialer{}
+	v.udp, err = dialer.DialContext(ctx, "udp", net.JoinHostPort(readyData.IP, strconv.Itoa(readyData.Port)))
+	if err != nil {
+		return err
+	}
+	ip, port, err := v.discoverIP(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = v.send(voiceOpcodeSelectProtocol, map[string]any{
+		"protocol": "udp",
+		"data": map[string]any{
+			"address": ip,
+			"port":    port,
+			"mode":    v.mode,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	description, err := v.waitFor(voiceOpcodeSessionDescription)
+	if err != nil {
+		return err
+	}
+	var descriptionData struct {
+		Mode      VoiceEncryptionMode `json:"mode"`
+		SecretKey [32]byte            `json:"secret_key"`
+	}
+	if err := json.Unmarshal(description, &descriptionData); err != nil {
+		return err
+	}
+	v.aead, err = newVoiceAEAD(descriptionData.Mode, descriptionData.SecretKey[:])
+	return err
+}
+
+// discoverIP finds the external address and port of the UDP connection.
+//
+// Reference: https://discord.com/developers/docs/topics/voice-connections#ip-discovery
+func (v *VoiceConnection) discoverIP(ctx context.Context) (string, int, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		v.udp.SetDeadline(deadline)
+		defer v.udp.SetDeadline(time.Time{})
+	}
+
+	packet := make([]byte, 74)
+	binary.BigEndian.PutUint16(packet[0:], 0x1) // request
+	binary.BigEndian.PutUint16(packet[2:], 70)  // length
+	binary.BigEndian.PutUint32(packet[4:], v.ssrc)
+	if _, err := v.udp.Write(packet); err != nil {
+		return "", 0, err
+	}
+
+	n, err := v.udp.Read(packet)
+	if err != nil {
+		return "", 0, err
+	}
+	if n < 74 || binary.BigEndian.Uint16(packet[0:]) != 0x2 {
+		return "", 0, errVoiceIPDiscovery
+	}
+
+	address := packet[8:72]
+	if i := slices.Index(address, 0); i >= 0 {
+		address = address[:i]
+	}
+	return string(address), int(binary.BigEndian.Uint16(packet[72:])), nil
+}
+
+// newVoiceAEAD creates the cipher of an encryption mode.
+func newVoiceAEAD(mode VoiceEncryptionMode, key []byte) (cipher.AEAD, error) {
+	switch mode {
+	case VoiceEncryptionModeAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case VoiceEncryptionModeXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, errVoiceNoEncryptionMode
+	}
+}
+
+// waitFor reads payloads until one with the given opcode is received, returning its data.
+func (v *VoiceConnection) waitFor(op voiceOpcode) (json.RawMessage, error) {
+	for {
+		payload, err := v.readPayload()
+		if err != nil {
+			return nil, err
+		}
+		if payload.Op == op {
+			return payload.D, nil
+		}
+		v.handlePayload(payload)
+	}
+}
+
+// readPayload reads the next JSON payload, skipping binary ones.
+func (v *VoiceConnection) readPayload() (voicePayload, error) {
+	for {
+		msg, op, err := wsutil.ReadServerData(v.conn)
+		if err != nil {
+			return voicePayload{}, err
+		}
+		if op != ws.OpText {
+			// binary payloads are only used by DAVE
+			continue
+		}
+
+		var payload voicePayload
+		if err := json.Unmarshal(msg, &payload); err != nil {
+			return voicePayload{}, errVoiceUnexpectedPayload
+		}
+		if payload.Seq != nil {
+			v.seq.Store(*payload.Seq)
+		}
+		return payload, nil
+	}
+}
+
+// handlePayload handles payloads received after the handshake.
+func (v *VoiceConnection) handlePayload(payload voicePayload) {
+	switch payload.Op {
+	case voiceOpcodeHeartbeatACK:
+		var ack struct {
+			T int64 `json:"t"`
+		}
+		if err := json.Unmarshal(payload.D, &ack); err == nil {
+			v.latency.Store(MonotonicNow()/int64(time.Millisecond) - ack.T)
+		}
+	default:
+		v.logger.Debug("Voice connection ignored opcode " + strconv.Itoa(int(payload.Op)))
+	}
+}
+
+// readLoop reads voice Gateway payloads until the connection is closed.
+func (v *VoiceConnection) readLoop() {
+	for {
+		payload, err := v.readPayload()
+		if err != nil {
+			select {
+			case <-v.closed:
+			default:
+				v.logger.Error("Voice connection to guild " + v.session.GuildID.String() + " read error: " + err.Error())
+				v.Close()
+			}
+			return
+		}
+		v.handlePayload(payload)
+	}
+}
+
+// heartbeat sends heartbeats at the given interval until the connection is closed.
+func (v *VoiceConnection) heartbeat(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-v.closed:
+			return
+		case <-ticker.C:
+		}
+
+		err := v.send(voiceOpcodeHeartbeat, map[string]any{
+			"t":       MonotonicNow() / int64(time.Millisecond),
+			"seq_ack": v.seq.Load(),
+		})
+		if err != nil {
+			v.logger.Error("Voice connection to guild " + v.session.GuildID.String() + " heartbeat error: " + err.Error())
+			v.Close()
+			return
+		}
+	}
+}
+
+// send writes a payload to the voice Gateway.
+func (v *VoiceConnection) send(op voiceOpcode, data any) error {
+	payload, err := json.Marshal(map[string]any{"op": op, "d": data})
+	if err != nil {
+		return err
+	}
+
+	v.writeMu.Lock()
+	defer v.writeMu.Unlock()
+	return wsutil.WriteClientMessage(v.conn, ws.OpText, payload)
+}
+
+// Speaking sets the speaking state of the bot, it must be set before sending audio.
+//
+// Use 0 to stop speaking.
+func (v *VoiceConnection) Speaking(flags VoiceSpeakingFlags) error {
+	return v.send(voiceOpcodeSpeaking, map[string]any{
+		"speaking": flags,
+		"delay":    0,
+		"ssrc":     v.ssrc,
+	})
+}
+
+// SendOpus encrypts and sends a single Opus packet.
+//
+// The caller is responsible for pacing, one packet every 20ms.
+// PlayOpus handles pacing and the speaking state.
+func (v *VoiceConnection) SendOpus(opus []byte) error {
+	select {
+	case <-v.closed:
+		return ErrVoiceConnectionClosed
+	default:
+	}
+
+	v.sendMu.Lock()
+	defer v.sendMu.Unlock()
+
+	if cap(v.packet) < rtpHeaderSize {
+		v.packet = make([]byte, rtpHeaderSize, rtpHeaderSize+len(opus)+64)
+	}
+	packet := v.packet[:rtpHeaderSize]
+	packet[0] = 0x80 // version 2
+	packet[1] = 0x78 // payload type 120 (Opus)
+	binary.BigEndian.PutUint16(packet[2:], v.sequence)
+	binary.BigEndian.PutUint32(packet[4:], v.timestamp)
+	binary.BigEndian.PutUint32(packet[8:], v.ssrc)
+
+	// rtpsize modes use a 32 bit counter nonce, appended to the packet
+	var nonce [chacha20poly1305.NonceSizeX]byte
+	binary.BigEndian.PutUint32(nonce[:], v.nonce)
+	packet = v.aead.Seal(packet, nonce[:v.aead.NonceSize()], opus, packet[:rtpHeaderSize])
+	packet = binary.BigEndian.AppendUint32(packet, v.nonce)
+	v.packet = packet
+
+	v.sequence++
+	v.timestamp += opusFrameSamples
+	v.nonce++
+
+	_, err := v.udp.Write(packet)
+	return err
+}
+
+// PlayOpus sends the Opus packets of src every 20ms until src returns io.EOF,
+// ctx is done or the connection is closed.
+//
+// Usage example:
+//
+//	err := vc.PlayOpus(ctx, myOpusReader)
+//
+// Returns:
+//   - error: nil once src is exhausted, otherwise the read, send or context error.
+func (v *VoiceConnection) PlayOpus(ctx context.Context, src OpusReader) error {
+	if err := v.Speaking(VoiceSpeakingFlagMicrophone); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(opusFrameDuration)
+	defer ticker.Stop()
+
+	var err error
+	for {
+		var opus []byte
+		opus, err = src.ReadOpus()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			break
+		}
+		if err = v.SendOpus(opus); err != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-v.closed:
+			err = ErrVoiceConnectionClosed
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+
+	if !errors.Is(err, ErrVoiceConnectionClosed) {
+		for range 5 {
+			v.SendOpus(opusSilenceFrame)
+		}
+		v.Speaking(0)
+	}
+	return err
+}
+
+// Latency returns the latency of the last voice heartbeat in milliseconds.
+func (v *VoiceConnection) Latency() int64 {
+	return v.latency.Load()
+}
+
+// Mode returns the encryption mode selected for the connection.
+func (v *VoiceConnection) Mode() VoiceEncryptionMode {
+	return v.mode
+}
+
+// Close closes the voice connection, the bot stays in the voice channel,
+// use Client.LeaveVoice to leave it.
+func (v *VoiceConnection) Close() error {
+	var err error
+	v.closeOnce.Do(func() {
+		close(v.closed)
+
+		v.writeMu.Lock()
+		if v.conn != nil {
+			err = v.conn.Close()
+		}
+		v.writeMu.Unlock()
+
+		if v.udp != nil {
+			v.udp.Close()
+		}
+		v.logger.Debug("Voice connection to guild " + v.session.GuildID.String() + " closed")
+	})
+	return err
+}
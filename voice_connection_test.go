@@ -0,0 +1,268 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+// fakeVoiceServer is a local voice server speaking enough of the voice
+// Gateway and UDP protocols to receive audio.
+type fakeVoiceServer struct {
+	t       *testing.T
+	http    *httptest.Server
+	udp     net.PacketConn
+	mode    VoiceEncryptionMode
+	key     [32]byte
+	opcodes chan voiceOpcode // opcodes received from the client
+	packets chan []byte      // RTP packets received from the client
+}
+
+func newFakeVoiceServer(t *testing.T, mode VoiceEncryptionMode) *fakeVoiceServer {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeVoiceServer{
+		t:       t,
+		udp:     udp,
+		mode:    mode,
+		opcodes: make(chan voiceOpcode, 64),
+		packets: make(chan []byte, 64),
+	}
+	for i := range s.key {
+		s.key[i] = byte(i)
+	}
+	s.http = httptest.NewServer(http.HandlerFunc(s.serveWS))
+	go s.serveUDP()
+	t.Cleanup(func() {
+		s.http.Close()
+		s.udp.Close()
+	})
+	return s
+}
+
+func (s *fakeVoiceServer) endpoint() string {
+	return strings.Replace(s.http.URL, "http://", "ws://", 1)
+}
+
+func (s *fakeVoiceServer) serveUDP() {
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := s.udp.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if n == 74 && binary.BigEndian.Uint16(buf) == 0x1 {
+			resp := make([]byte, 74)
+			binary.BigEndian.PutUint16(resp[0:], 0x2)
+			binary.BigEndian.PutUint16(resp[2:], 70)
+			copy(resp[4:8], buf[4:8])
+			copy(resp[8:], "203.0.113.7")
+			binary.BigEndian.PutUint16(resp[72:], 50000)
+			s.udp.WriteTo(resp, addr)
+			continue
+		}
+		s.packets <- bytes.Clone(buf[:n])
+	}
+}
+
+func (s *fakeVoiceServer) serveWS(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("v") != voiceGatewayVersion {
+		s.t.Errorf("unexpected voice gateway version %q", r.URL.Query().Get("v"))
+	}
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	write := func(op voiceOpcode, d any) {
+		data, _ := json.Marshal(d)
+		payload, _ := json.Marshal(voicePayload{Op: op, D: data})
+		wsutil.WriteServerMessage(conn, ws.OpText, payload)
+	}
+
+	write(voiceOpcodeHello, map[string]any{"heartbeat_interval": 50})
+	for {
+		msg, _, err := wsutil.ReadClientData(conn)
+		if err != nil {
+			return
+		}
+		var p voicePayload
+		if err := json.Unmarshal(msg, &p); err != nil {
+			s.t.Errorf("invalid client payload: %s", msg)
+			return
+		}
+		s.opcodes <- p.Op
+
+		switch p.Op {
+		case voiceOpcodeIdentify:
+			var identify struct {
+				ServerID  Snowflake `json:"server_id"`
+				SessionID string    `json:"session_id"`
+				Token     string    `json:"token"`
+			}
+			json.Unmarshal(p.D, &identify)
+			if identify.ServerID != 1 || identify.SessionID != "session" || identify.Token != "token" {
+				s.t.Errorf("unexpected identify: %s", p.D)
+			}
+			port := s.udp.LocalAddr().(*net.UDPAddr).Port
+			write(voiceOpcodeReady, map[string]any{
+				"ssrc": 42, "ip": "127.0.0.1", "port": port,
+				"modes": []VoiceEncryptionMode{"xsalsa20_poly1305", s.mode},
+			})
+		case voiceOpcodeSelectProtocol:
+			var sel struct {
+				Data struct {
+					Address string              `json:"address"`
+					Port    int                 `json:"port"`
+					Mode    VoiceEncryptionMode `json:"mode"`
+				} `json:"data"`
+			}
+			json.Unmarshal(p.D, &sel)
+			if sel.Data.Address != "203.0.113.7" || sel.Data.Port != 50000 || sel.Data.Mode != s.mode {
+				s.t.Errorf("unexpected select protocol: %s", p.D)
+			}
+			write(voiceOpcodeSessionDescription, map[string]any{"mode": s.mode, "secret_key": s.key})
+		case voiceOpcodeHeartbeat:
+			var hb struct {
+				T int64 `json:"t"`
+			}
+			json.Unmarshal(p.D, &hb)
+			write(voiceOpcodeHeartbeatACK, map[string]any{"t": hb.T})
+		}
+	}
+}
+
+// open returns a connection opened on the fake server.
+func (s *fakeVoiceServer) open() *VoiceConnection {
+	vc := NewVoiceConnection(VoiceSession{
+		GuildID: 1, ChannelID: 2, UserID: 3,
+		SessionID: "session", Token: "token", Endpoint: s.endpoint(),
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := vc.Open(ctx); err != nil {
+		s.t.Fatalf("open: %v", err)
+	}
+	s.t.Cleanup(func() { vc.Close() })
+	return vc
+}
+
+// opusFrames is an OpusReader over a fixed list of packets.
+type opusFrames [][]byte
+
+func (f *opusFrames) ReadOpus() ([]byte, error) {
+	if len(*f) == 0 {
+		return nil, io.EOF
+	}
+	frame := (*f)[0]
+	*f = (*f)[1:]
+	return frame, nil
+}
+
+func TestVoiceConnection_PlayOpus(t *testing.T) {
+	for _, mode := range []VoiceEncryptionMode{VoiceEncryptionModeAES256GCM, VoiceEncryptionModeXChaCha20Poly1305} {
+		t.Run(string(mode), func(t *testing.T) {
+			server := newFakeVoiceServer(t, mode)
+			vc := server.open()
+			if vc.Mode() != mode {
+				t.Fatalf("expected mode %s got %s", mode, vc.Mode())
+			}
+
+			want := [][]byte{[]byte("opus-1"), []byte("opus-2"), []byte("opus-3")}
+			src := opusFrames(want)
+			if err := vc.PlayOpus(context.Background(), &src); err != nil {
+				t.Fatalf("play: %v", err)
+			}
+
+			aead, err := newVoiceAEAD(mode, server.key[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the audio is followed by 5 silence frames
+			for i := range len(want) + 5 {
+				var packet []byte
+				select {
+				case packet = <-server.packets:
+				case <-time.After(time.Second):
+					t.Fatalf("packet %d not received", i)
+				}
+
+				header := packet[:rtpHeaderSize]
+				if header[0] != 0x80 || header[1] != 0x78 ||
+					binary.BigEndian.Uint16(header[2:]) != uint16(i) ||
+					binary.BigEndian.Uint32(header[4:]) != uint32(i*opusFrameSamples) ||
+					binary.BigEndian.Uint32(header[8:]) != 42 {
+					t.Fatalf("invalid rtp header %x", header)
+				}
+
+				nonce := make([]byte, aead.NonceSize())
+				copy(nonce, packet[len(packet)-4:])
+				opus, err := aead.Open(nil, nonce, packet[rtpHeaderSize:len(packet)-4], header)
+				if err != nil {
+					t.Fatalf("decrypt packet %d: %v", i, err)
+				}
+
+				expected := opusSilenceFrame
+				if i < len(want) {
+					expected = want[i]
+				}
+				if !bytes.Equal(opus, expected) {
+					t.Fatalf("packet %d: expected %q got %q", i, expected, opus)
+				}
+			}
+		})
+	}
+}
+
+func TestVoiceConnection_HeartbeatAndSpeaking(t *testing.T) {
+	server := newFakeVoiceServer(t, VoiceEncryptionModeXChaCha20Poly1305)
+	vc := server.open()
+
+	if err := vc.Speaking(VoiceSpeakingFlagMicrophone); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[voiceOpcode]bool{}
+	deadline := time.After(2 * time.Second)
+	for !seen[voiceOpcodeHeartbeat] || !seen[voiceOpcodeSpeaking] {
+		select {
+		case op := <-server.opcodes:
+			seen[op] = true
+		case <-deadline:
+			t.Fatalf("missing heartbeat or speaking, got %v", seen)
+		}
+	}
+
+	vc.Close()
+	if err := vc.SendOpus([]byte("late")); err != ErrVoiceConnectionClosed {
+		t.Fatalf("expected ErrVoiceConnectionClosed got %v", err)
+	}
+}
@@ -13,7 +13,10 @@
 
 package goda
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 // VoiceState represents a user's voice connection status in a guild.
 //
@@ -59,6 +62,80 @@ type VoiceState struct {
 	RequestToSpeakTimestamp *time.Time `json:"request_to_speak_timestamp,omitempty"`
 }
 
+// VoiceSession holds the data needed to open a voice connection,
+// received from the Gateway after joining a voice channel.
+//
+// Reference: https://discord.com/developers/docs/topics/voice-connections#retrieving-voice-server-information
+type VoiceSession struct {
+	// GuildID is the ID of the guild of the voice channel.
+	GuildID Snowflake
+
+	// ChannelID is the ID of the joined voice channel.
+	ChannelID Snowflake
+
+	// UserID is the ID of the bot user.
+	UserID Snowflake
+
+	// SessionID is the voice state session ID of the bot.
+	SessionID string
+
+	// Token is the voice connection token.
+	Token string
+
+	// Endpoint is the voice server host.
+	Endpoint string
+}
+
+// voiceJoin waits for the VOICE_STATE_UPDATE and VOICE_SERVER_UPDATE
+// answering a voice channel join.
+type voiceJoin struct {
+	mu        sync.Mutex
+	session   VoiceSession
+	gotState  bool
+	gotServer bool
+	done      chan struct{}
+}
+
+// setState stores the bot's voice state session.
+func (j *voiceJoin) setState(state VoiceState) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.session.ChannelID = state.ChannelID
+	j.session.UserID = state.UserID
+	j.session.SessionID = state.SessionID
+	j.gotState = true
+	j.complete()
+}
+
+// setServer stores the voice server of the guild.
+func (j *voiceJoin) setServer(evt VoiceServerUpdateEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// a null endpoint means the voice server is not allocated yet,
+	// another VOICE_SERVER_UPDATE will follow
+	if evt.Endpoint == "" {
+		return
+	}
+	j.session.Token = evt.Token
+	j.session.Endpoint = evt.Endpoint
+	j.gotServer = true
+	j.complete()
+}
+
+// complete closes done once both events were received, j.mu must be held.
+func (j *voiceJoin) complete() {
+	if !j.gotState || !j.gotServer {
+		return
+	}
+	select {
+	case <-j.done:
+	default:
+		close(j.done)
+	}
+}
+
 // VoiceRegion represents a Discord voice region.
 //
 // Reference: https://discord.com/developers/docs/resources/voice#voice-region-object
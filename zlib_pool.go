@@ -15,7 +15,9 @@ package goda
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/zlib"
+	"errors"
 	"io"
 	"sync"
 )
@@ -24,21 +26,42 @@ import (
 // This indicates the end of a complete zlib-compressed payload.
 var zlibSuffix = []byte{0x00, 0x00, 0xff, 0xff}
 
-// zlibReaderWrapper wraps a zlib.Reader with a reusable buffer.
-// This allows the reader to be reused across multiple decompressions,
-// avoiding the allocation overhead of creating new readers.
+// zlibWindowSize is the size of the deflate sliding window.
+// Back-references in a zlib-stream never reach further than this.
+const zlibWindowSize = 32 * 1024
+
+// errInvalidZlibHeader is returned when the first message of a zlib-stream
+// does not start with a valid zlib header.
+var errInvalidZlibHeader = errors.New("goda: invalid zlib-stream header")
+
+// zlibReaderWrapper holds the inflate context of a single zlib-stream.
+//
+// Discord's zlib-stream transport compression uses one deflate stream for the
+// whole lifetime of a connection, each payload ending with a sync flush. The
+// wrapper keeps the last 32KiB of decompressed output and feeds it back as the
+// preset dictionary of the next payload, so back-references across payloads
+// resolve correctly while each Decompress call stays self-contained.
+//
+// A wrapper must only be used for a single connection; release it and acquire
+// a new one when the connection is re-established.
 type zlibReaderWrapper struct {
-	reader io.ReadCloser
-	buf    bytes.Buffer
+	reader     io.ReadCloser // flate reader, reset for every complete payload
+	src        bytes.Reader  // reader over the buffered compressed payload
+	buf        bytes.Buffer  // compressed data accumulated until zlibSuffix
+	out        bytes.Buffer  // decompressed output of the last payload
+	window     []byte        // last zlibWindowSize bytes of decompressed output
+	headerRead bool          // true once the 2-byte zlib header was consumed
 }
 
+var _ GatewayDecompressor = (*zlibReaderWrapper)(nil)
+
 // zlibReaderPool provides reusable zlib readers for decompressing
 // gateway messages. Using a pool prevents memory spikes during
 // high-traffic events by recycling decompressor instances.
 var zlibReaderPool = sync.Pool{
 	New: func() any {
 		return &zlibReaderWrapper{
-			buf: bytes.Buffer{},
+			window: make([]byte, 0, zlibWindowSize),
 		}
 	},
 }
@@ -58,16 +81,36 @@ func ReleaseZlibReader(w *zlibReaderWrapper) {
 		w.reader.Close()
 		w.reader = nil
 	}
-	// Reset the buffer
+	// Reset the stream state
+	w.src.Reset(nil)
 	w.buf.Reset()
+	w.out.Reset()
+	w.window = w.window[:0]
+	w.headerRead = false
 	zlibReaderPool.Put(w)
 }
 
-// Decompress decompresses zlib-compressed data from the gateway.
-// Returns the decompressed data as a byte slice.
+// newZlibStreamDecompressor is the GatewayDecompressorFactory used for
+// GatewayCompressionZlibStream.
+func newZlibStreamDecompressor() GatewayDecompressor {
+	return AcquireZlibReader()
+}
+
+// Close returns the wrapper to the pool.
+//
+// The wrapper must not be used after Close.
+func (w *zlibReaderWrapper) Close() error {
+	ReleaseZlibReader(w)
+	return nil
+}
+
+// Decompress decompresses zlib-stream data from the gateway.
+// Returns the decompressed payload, or nil if the payload is not complete yet.
 //
 // The wrapper's internal buffer is used to accumulate compressed data
 // until a complete message (ending with zlibSuffix) is received.
+//
+// The returned slice is only valid until the next call to Decompress.
 func (w *zlibReaderWrapper) Decompress(data []byte) ([]byte, error) {
 	// Write incoming data to buffer
 	w.buf.Write(data)
@@ -78,34 +121,53 @@ func (w *zlibReaderWrapper) Decompress(data []byte) ([]byte, error) {
 		return nil, nil
 	}
 
-	// Create or reset the zlib reader
-	if w.reader == nil {
-		reader, err := zlib.NewReader(&w.buf)
-		if err != nil {
-			return nil, err
-		}
-		w.reader = reader
-	} else {
-		// Reset for new decompression
-		if resetter, ok := w.reader.(zlib.Resetter); ok {
-			if err := resetter.Reset(&w.buf, nil); err != nil {
-				return nil, err
-			}
+	compressed := w.buf.Bytes()
+	if !w.headerRead {
+		if !IsZlibCompressed(compressed) {
+			w.buf.Reset()
+			return nil, errInvalidZlibHeader
 		}
+		compressed = compressed[2:]
+		w.headerRead = true
+	}
+	w.src.Reset(compressed)
+
+	// Create or reset the flate reader with the previous output as dictionary
+	if w.reader == nil {
+		w.reader = flate.NewReaderDict(&w.src, w.window)
+	} else if err := w.reader.(flate.Resetter).Reset(&w.src, w.window); err != nil {
+		return nil, err
 	}
 
-	// Read decompressed data
-	decompressed, err := io.ReadAll(w.reader)
-	if err != nil && err != io.EOF {
+	// The stream never terminates, so reading stops with an unexpected EOF
+	// right after the sync flush; every byte before it was already returned.
+	w.out.Reset()
+	if _, err := w.out.ReadFrom(w.reader); err != nil && err != io.ErrUnexpectedEOF {
+		w.buf.Reset()
 		return nil, err
 	}
 
 	// Clear buffer for next message
 	w.buf.Reset()
 
+	decompressed := w.out.Bytes()
+	w.slideWindow(decompressed)
 	return decompressed, nil
 }
 
+// slideWindow appends p to the dictionary window, keeping only the
+// last zlibWindowSize bytes.
+func (w *zlibReaderWrapper) slideWindow(p []byte) {
+	if len(p) >= zlibWindowSize {
+		w.window = append(w.window[:0], p[len(p)-zlibWindowSize:]...)
+		return
+	}
+	if overflow := len(w.window) + len(p) - zlibWindowSize; overflow > 0 {
+		w.window = append(w.window[:0], w.window[overflow:]...)
+	}
+	w.window = append(w.window, p...)
+}
+
 // DecompressOneShot decompresses a single zlib-compressed message.
 // This is a convenience function for one-off decompression.
 // For streaming decompression (Discord gateway), use the pooled wrapper.
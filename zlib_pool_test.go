@@ -0,0 +1,86 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"bytes"
+	"compress/zlib"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// zlibStreamFrames compresses payloads the way Discord does for zlib-stream:
+// a single zlib stream with a sync flush after every payload.
+func zlibStreamFrames(tb testing.TB, payloads [][]byte) [][]byte {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	frames := make([][]byte, 0, len(payloads))
+	for _, p := range payloads {
+		if _, err := zw.Write(p); err != nil {
+			tb.Fatalf("compress: %v", err)
+		}
+		if err := zw.Flush(); err != nil {
+			tb.Fatalf("flush: %v", err)
+		}
+		frames = append(frames, bytes.Clone(buf.Bytes()))
+		buf.Reset()
+	}
+	return frames
+}
+
+func TestZlibReader_Stream(t *testing.T) {
+	payloads := make([][]byte, 0, 50)
+	for i := range 50 {
+		// repeated content forces back-references into previous payloads
+		payloads = append(payloads, []byte(`{"op":0,"t":"MESSAGE_CREATE","s":`+strconv.Itoa(i)+
+			`,"d":{"content":"`+strings.Repeat("hello goda ", 200+i)+`"}}`))
+	}
+
+	w := AcquireZlibReader()
+	defer ReleaseZlibReader(w)
+
+	for i, frame := range zlibStreamFrames(t, payloads) {
+		out, err := w.Decompress(frame)
+		if err != nil {
+			t.Fatalf("payload %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, payloads[i]) {
+			t.Fatalf("payload %d: decompressed data mismatch", i)
+		}
+	}
+}
+
+func TestZlibReader_SplitFrames(t *testing.T) {
+	payload := `{"op":10,"d":{"heartbeat_interval":41250}}`
+	frame := zlibStreamFrames(t, [][]byte{[]byte(payload)})[0]
+
+	w := AcquireZlibReader()
+	defer ReleaseZlibReader(w)
+
+	out, err := w.Decompress(frame[:len(frame)/2])
+	if err != nil || out != nil {
+		t.Fatalf("expected partial payload to be buffered, got %q, %v", out, err)
+	}
+
+	out, err = w.Decompress(frame[len(frame)/2:])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != payload {
+		t.Fatalf("expected %q got %q", payload, out)
+	}
+}
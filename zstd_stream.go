@@ -0,0 +1,114 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/ra7eemi/goda/internal/zstd"
+)
+
+// zstdStreamReadSize is the size of the reads from the zstd reader,
+// the largest decompressed zstd block.
+const zstdStreamReadSize = 128 * 1024
+
+// zstdStreamSource is the io.Reader over the compressed message being decompressed.
+//
+// It returns io.EOF once the message is consumed.
+type zstdStreamSource struct {
+	data []byte
+}
+
+func (s *zstdStreamSource) Read(p []byte) (int, error) {
+	if len(s.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, s.data)
+	s.data = s.data[n:]
+	return n, nil
+}
+
+// zstdStreamDecompressor holds the decompression context of a single zstd-stream.
+//
+// Discord's zstd-stream transport compression uses one zstd frame for the
+// whole lifetime of a connection, each message ending with a flush. A flush
+// ends the current block, so every message holds whole blocks and is
+// decompressed as soon as it is received.
+//
+// A decompressor must only be used for a single connection.
+type zstdStreamDecompressor struct {
+	reader *zstd.Reader     // decompression context, kept across messages
+	src    zstdStreamSource // compressed message being decompressed
+	out    []byte           // decompressed output of the last message
+}
+
+var _ GatewayDecompressor = (*zstdStreamDecompressor)(nil)
+
+// zstdStreamPool provides reusable zstd decompressors, as their
+// window and block buffers are large.
+var zstdStreamPool = sync.Pool{
+	New: func() any {
+		d := &zstdStreamDecompressor{}
+		d.reader = zstd.NewReader(&d.src)
+		return d
+	},
+}
+
+// newZstdStreamDecompressor is the GatewayDecompressorFactory used for
+// GatewayCompressionZstdStream.
+func newZstdStreamDecompressor() GatewayDecompressor {
+	return zstdStreamPool.Get().(*zstdStreamDecompressor)
+}
+
+// Close resets the decompressor and returns it to the pool.
+//
+// The decompressor must not be used after Close.
+func (d *zstdStreamDecompressor) Close() error {
+	d.src.data = nil
+	d.reader.Reset(&d.src)
+	d.out = d.out[:0]
+	zstdStreamPool.Put(d)
+	return nil
+}
+
+// Decompress decompresses a zstd-stream message from the gateway.
+//
+// The returned slice is only valid until the next call to Decompress.
+func (d *zstdStreamDecompressor) Decompress(data []byte) ([]byte, error) {
+	d.src.data = data
+	d.out = d.out[:0]
+	for {
+		empty := len(d.src.data) == 0
+
+		// each read returns a whole block, so the message ends with its input
+		if cap(d.out)-len(d.out) < zstdStreamReadSize {
+			d.out = append(d.out, make([]byte, zstdStreamReadSize)...)[:len(d.out)]
+		}
+		n, err := d.reader.Read(d.out[len(d.out):cap(d.out)])
+		d.out = d.out[:len(d.out)+n]
+		if err != nil {
+			// an empty message fails reading the next block header
+			if empty && (err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF)) {
+				return d.out, nil
+			}
+			d.src.data = nil
+			return nil, err
+		}
+		if len(d.src.data) == 0 {
+			return d.out, nil
+		}
+	}
+}
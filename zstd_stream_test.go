@@ -0,0 +1,100 @@
+/************************************************************************************
+ *
+ * goda (Golang Optimized Discord API), A Lightweight Go library for Discord API
+ *
+ * SPDX-License-Identifier: BSD-3-Clause
+ *
+ * Copyright 2025 Marouane Souiri
+ *
+ * Licensed under the BSD 3-Clause License.
+ * See the LICENSE file for details.
+ *
+ ************************************************************************************/
+
+package goda
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+// zstdStreamFrames returns the zstd-stream messages of payloads, compressed
+// by the reference library with testdata/gen_zstd_stream.go, after checking
+// they decompress to payloads.
+func zstdStreamFrames(tb testing.TB, payloads [][]byte) [][]byte {
+	tb.Helper()
+
+	path := os.Getenv("GODA_GATEWAY_RECORDING_ZSTD")
+	if path == "" {
+		if os.Getenv("GODA_GATEWAY_RECORDING") != "" {
+			tb.Skip("GODA_GATEWAY_RECORDING_ZSTD is not set")
+		}
+		path = "testdata/zstd_stream.bin"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("read zstd recording: %v", err)
+	}
+
+	var frames [][]byte
+	for len(data) > 0 {
+		if len(data) < 4 || int(binary.LittleEndian.Uint32(data)) > len(data)-4 {
+			tb.Fatalf("truncated zstd recording %s", path)
+		}
+		n := int(binary.LittleEndian.Uint32(data))
+		frames = append(frames, data[4:4+n])
+		data = data[4+n:]
+	}
+
+	if len(frames) != len(payloads) {
+		tb.Fatalf("%s holds %d messages, expected %d: regenerate it with testdata/gen_zstd_stream.go", path, len(frames), len(payloads))
+	}
+	d := newZstdStreamDecompressor()
+	defer d.Close()
+	for i, frame := range frames {
+		out, err := d.Decompress(frame)
+		if err != nil {
+			tb.Fatalf("payload %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(out, payloads[i]) {
+			tb.Fatalf("payload %d: %s does not match the recording: regenerate it with testdata/gen_zstd_stream.go", i, path)
+		}
+	}
+	return frames
+}
+
+func TestZstdStreamDecompressor_Reuse(t *testing.T) {
+	payloads := syntheticGatewayRecording()
+	frames := zstdStreamFrames(t, payloads)
+
+	// a pooled decompressor starts a new stream for every connection
+	for conn := range 3 {
+		d := newZstdStreamDecompressor()
+		for i, frame := range frames[:10] {
+			out, err := d.Decompress(frame)
+			if err != nil {
+				t.Fatalf("connection %d, payload %d: unexpected error: %v", conn, i, err)
+			}
+			if !bytes.Equal(out, payloads[i]) {
+				t.Fatalf("connection %d, payload %d: decompressed data mismatch", conn, i)
+			}
+		}
+		d.Close()
+	}
+}
+
+func TestZstdStreamDecompressor_Truncated(t *testing.T) {
+	frames := zstdStreamFrames(t, syntheticGatewayRecording())
+
+	d := newZstdStreamDecompressor()
+	defer d.Close()
+
+	if _, err := d.Decompress(frames[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out, err := d.Decompress(frames[1][:len(frames[1])/2]); err == nil {
+		t.Fatalf("expected an error for a truncated message, got %d bytes", len(out))
+	}
+}